@@ -0,0 +1,205 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// AppliedDirective a directive used in the type definitions with its parsed arguments
+type AppliedDirective struct {
+	Name string
+	Args map[string]interface{}
+	Node *ast.Directive
+}
+
+// AppliedDirectives an index of the directives used in the type definitions of
+// a schema. Directives are looked up by coordinate, "Type", "Type.field",
+// "Type.field.arg", "Enum.VALUE" or "Input.field", and the schema definition
+// uses "schema". Directives of type extensions are included
+type AppliedDirectives struct {
+	coordinates map[string][]*AppliedDirective
+}
+
+// creates an empty index
+func newAppliedDirectives() *AppliedDirectives {
+	return &AppliedDirectives{
+		coordinates: map[string][]*AppliedDirective{},
+	}
+}
+
+// Schema returns the directives applied to the schema definition
+func (c *AppliedDirectives) Schema() []*AppliedDirective {
+	return c.coordinates["schema"]
+}
+
+// Coordinate returns the directives applied at a coordinate
+func (c *AppliedDirectives) Coordinate(coordinate string) []*AppliedDirective {
+	return c.coordinates[coordinate]
+}
+
+// Type returns the directives applied to a type
+func (c *AppliedDirectives) Type(typeName string) []*AppliedDirective {
+	return c.coordinates[typeName]
+}
+
+// Field returns the directives applied to a field of an object or interface
+func (c *AppliedDirectives) Field(typeName, fieldName string) []*AppliedDirective {
+	return c.coordinates[typeName+"."+fieldName]
+}
+
+// Argument returns the directives applied to a field argument
+func (c *AppliedDirectives) Argument(typeName, fieldName, argName string) []*AppliedDirective {
+	return c.coordinates[typeName+"."+fieldName+"."+argName]
+}
+
+// EnumValue returns the directives applied to an enum value
+func (c *AppliedDirectives) EnumValue(enumName, value string) []*AppliedDirective {
+	return c.coordinates[enumName+"."+value]
+}
+
+// InputField returns the directives applied to an input object field
+func (c *AppliedDirectives) InputField(inputName, fieldName string) []*AppliedDirective {
+	return c.coordinates[inputName+"."+fieldName]
+}
+
+// gets the coordinate of the config a directive is applied to
+func appliedDirectiveCoordinate(p applyDirectiveParams) string {
+	switch config := p.config.(type) {
+	case *graphql.SchemaConfig:
+		return "schema"
+	case *graphql.ScalarConfig:
+		return config.Name
+	case *graphql.ObjectConfig:
+		return config.Name
+	case *graphql.InterfaceConfig:
+		return config.Name
+	case *graphql.UnionConfig:
+		return config.Name
+	case *graphql.EnumConfig:
+		return config.Name
+	case *graphql.InputObjectConfig:
+		return config.Name
+	case *graphql.Field:
+		return p.parentName + "." + config.Name
+	case *graphql.EnumValueConfig:
+		return p.parentName + "." + p.node.(*ast.EnumValueDefinition).Name.Value
+	case *graphql.InputObjectFieldConfig:
+		return p.parentName + "." + p.node.(*ast.InputValueDefinition).Name.Value
+	case *graphql.ArgumentConfig:
+		// arguments of directive definitions are not indexed
+		if p.field == nil {
+			return ""
+		}
+		return p.parentName + "." + p.field.Name + "." + p.node.(*ast.InputValueDefinition).Name.Value
+	}
+	return ""
+}
+
+// records the directives used in the type definitions at a location
+func (c *registry) indexDirectives(p applyDirectiveParams) error {
+	applied := []*AppliedDirective{}
+	for _, def := range p.directives {
+		name := def.Name.Value
+		directive, err := c.getDirective(name)
+		if err != nil {
+			return err
+		}
+
+		args, err := GetArgumentValues(directive.Args, def.Arguments, map[string]interface{}{})
+		if err != nil {
+			return newDirectiveError(def, "invalid arguments for directive @%s: %s", name, err)
+		}
+
+		applied = append(applied, &AppliedDirective{
+			Name: name,
+			Args: args,
+			Node: def,
+		})
+	}
+
+	// types may be built more than once so the directives are replaced
+	coordinate := appliedDirectiveCoordinate(p)
+	switch {
+	case coordinate == "":
+	case len(applied) == 0:
+		delete(c.appliedDirectives.coordinates, coordinate)
+	default:
+		c.appliedDirectives.coordinates[coordinate] = applied
+	}
+	return nil
+}
+
+// context key of the applied directive index
+type appliedDirectivesKey struct{}
+
+// adds the applied directive index to the schema when directives are used
+func (c *AppliedDirectives) addToSchema(schema *graphql.Schema) {
+	if len(c.coordinates) > 0 {
+		schema.AddExtensions(&contextExtension{
+			name: "AppliedDirectives",
+			init: func(ctx context.Context) context.Context {
+				return context.WithValue(ctx, appliedDirectivesKey{}, c)
+			},
+		})
+	}
+}
+
+// AppliedDirectivesFromContext returns the applied directive index of the
+// schema executing a request or nil. The index is added to the context of
+// requests executed with graphql.Do
+func AppliedDirectivesFromContext(ctx context.Context) *AppliedDirectives {
+	if ctx == nil {
+		return nil
+	}
+	index, _ := ctx.Value(appliedDirectivesKey{}).(*AppliedDirectives)
+	return index
+}
+
+// FieldDirectives the directives used in the type definitions of the field
+// being resolved, its parent type and its arguments keyed by argument name
+type FieldDirectives struct {
+	Field     []*AppliedDirective
+	Parent    []*AppliedDirective
+	Arguments map[string][]*AppliedDirective
+}
+
+// DirectivesFor returns the directives used in the type definitions of the
+// field being resolved. Subscription resolvers do not receive the index in
+// their context and should use ExecutableSchema.AppliedDirectives instead
+func DirectivesFor(p graphql.ResolveParams) FieldDirectives {
+	return AppliedDirectivesFromContext(p.Context).For(p.Info)
+}
+
+// For returns the directives used in the type definitions of the field being
+// resolved
+func (c *AppliedDirectives) For(info graphql.ResolveInfo) FieldDirectives {
+	directives := FieldDirectives{
+		Field:     []*AppliedDirective{},
+		Parent:    []*AppliedDirective{},
+		Arguments: map[string][]*AppliedDirective{},
+	}
+
+	if c == nil || info.ParentType == nil {
+		return directives
+	}
+
+	typeName := info.ParentType.Name()
+	if field := c.Field(typeName, info.FieldName); field != nil {
+		directives.Field = field
+	}
+	if parent := c.Type(typeName); parent != nil {
+		directives.Parent = parent
+	}
+	if object, ok := info.ParentType.(*graphql.Object); ok {
+		if field, ok := object.Fields()[info.FieldName]; ok {
+			for _, arg := range field.Args {
+				if applied := c.Argument(typeName, info.FieldName, arg.PrivateName); applied != nil {
+					directives.Arguments[arg.PrivateName] = applied
+				}
+			}
+		}
+	}
+	return directives
+}
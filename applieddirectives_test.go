@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestAppliedDirectives(t *testing.T) {
+	var directives FieldDirectives
+
+	config := ExecutableSchema{
+		TypeDefs: `
+directive @meta(name: String!, value: Int = 1) on OBJECT | FIELD_DEFINITION | ARGUMENT_DEFINITION | ENUM_VALUE | INPUT_FIELD_DEFINITION
+
+enum Color {
+	RED @meta(name: "red")
+}
+
+input Filter {
+	color: Color @meta(name: "color", value: 2)
+}
+
+type Query @meta(name: "query") {
+	foo(filter: Filter @meta(name: "filter")): String @meta(name: "foo", value: 3)
+}`,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"foo": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							directives = DirectivesFor(p)
+							return "foo", nil
+						},
+					},
+				},
+			},
+		},
+	}
+	schema, err := config.Make(context.Background())
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ foo }`,
+	})
+	if r.HasErrors() {
+		t.Error(r.Errors)
+		return
+	}
+
+	if len(directives.Field) != 1 || directives.Field[0].Args["name"] != "foo" || directives.Field[0].Args["value"] != 3 {
+		t.Errorf("unexpected field directives %v", directives.Field)
+	}
+	if len(directives.Parent) != 1 || directives.Parent[0].Args["name"] != "query" || directives.Parent[0].Args["value"] != 1 {
+		t.Errorf("unexpected parent directives %v", directives.Parent)
+	}
+	if args := directives.Arguments["filter"]; len(args) != 1 || args[0].Args["name"] != "filter" {
+		t.Errorf("unexpected argument directives %v", directives.Arguments)
+	}
+
+	index := config.AppliedDirectives()
+	if index == nil {
+		t.Error("expected an applied directive index for the schema")
+		return
+	}
+	if values := index.EnumValue("Color", "RED"); len(values) != 1 || values[0].Args["name"] != "red" {
+		t.Errorf("unexpected enum value directives %v", values)
+	}
+	if fields := index.InputField("Filter", "color"); len(fields) != 1 || fields[0].Args["value"] != 2 {
+		t.Errorf("unexpected input field directives %v", fields)
+	}
+	if args := index.Argument("Query", "foo", "filter"); len(args) != 1 || args[0].Node.Name.Value != "meta" {
+		t.Errorf("unexpected argument directives %v", args)
+	}
+	if types := index.Type("Query"); len(types) != 1 || index.Coordinate("Query.foo")[0].Args["value"] != 3 {
+		t.Errorf("unexpected type directives %v", types)
+	}
+
+	// schemas without applied directives do not add the index to the context
+	empty := ExecutableSchema{
+		TypeDefs: `type Query { foo: String }`,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"foo": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							if AppliedDirectivesFromContext(p.Context) != nil {
+								return nil, errors.New("unexpected applied directive index")
+							}
+							return "foo", nil
+						},
+					},
+				},
+			},
+		},
+	}
+	emptySchema, err := empty.Make(context.Background())
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+	if r := graphql.Do(graphql.Params{Schema: emptySchema, RequestString: `{ foo }`}); r.HasErrors() {
+		t.Error(r.Errors)
+	}
+	if len(empty.AppliedDirectives().Coordinate("Query")) != 0 {
+		t.Error("expected no applied directives")
+	}
+}
@@ -0,0 +1,498 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// determines if a type is one of the scalars defined by the graphql spec
+func isSpecifiedScalar(name string) bool {
+	switch name {
+	case "String", "Int", "Float", "Boolean", "ID":
+		return true
+	}
+	return false
+}
+
+// the directives defined by this package which are only added to schemas that use them
+var toolDirectives = []*graphql.Directive{HideDirective, CostDirective, ConstraintDirective, AuthDirective}
+
+// determines if a directive is one of the directives defined by the graphql spec
+func isSpecifiedDirective(name string) bool {
+	switch name {
+	case graphql.IncludeDirective.Name, graphql.SkipDirective.Name, graphql.DeprecatedDirective.Name:
+		return true
+	}
+	return false
+}
+
+// determines if a directive is one of the directives defined by the graphql
+// spec or this package. Directives declared with the same name are not builtin
+func isBuiltinDirective(directive *graphql.Directive) bool {
+	switch directive {
+	case graphql.IncludeDirective, graphql.SkipDirective, graphql.DeprecatedDirective:
+		return true
+	}
+	for _, d := range toolDirectives {
+		if directive == d {
+			return true
+		}
+	}
+	return false
+}
+
+// gets the names of the directives used in the type definitions of a document
+func getDocumentDirectiveNames(document *ast.Document) map[string]bool {
+	names := map[string]bool{}
+	add := func(directives []*ast.Directive) {
+		for _, directive := range directives {
+			names[directive.Name.Value] = true
+		}
+	}
+	addArgs := func(args []*ast.InputValueDefinition) {
+		for _, arg := range args {
+			add(arg.Directives)
+		}
+	}
+	addFields := func(fields []*ast.FieldDefinition) {
+		for _, field := range fields {
+			add(field.Directives)
+			addArgs(field.Arguments)
+		}
+	}
+
+	for _, def := range document.Definitions {
+		switch d := def.(type) {
+		case *ast.TypeExtensionDefinition:
+			def = d.Definition
+		case *ScalarExtensionDefinition:
+			def = d.Definition
+		case *InterfaceExtensionDefinition:
+			def = d.Definition
+		case *UnionExtensionDefinition:
+			def = d.Definition
+		case *EnumExtensionDefinition:
+			def = d.Definition
+		case *InputObjectExtensionDefinition:
+			def = d.Definition
+		}
+
+		switch d := def.(type) {
+		case *ast.SchemaDefinition:
+			add(d.Directives)
+		case *ast.ScalarDefinition:
+			add(d.Directives)
+		case *ast.ObjectDefinition:
+			add(d.Directives)
+			addFields(d.Fields)
+		case *ast.InterfaceDefinition:
+			add(d.Directives)
+			addFields(d.Fields)
+		case *ast.UnionDefinition:
+			add(d.Directives)
+		case *ast.EnumDefinition:
+			add(d.Directives)
+			for _, value := range d.Values {
+				add(value.Directives)
+			}
+		case *ast.InputObjectDefinition:
+			add(d.Directives)
+			addArgs(d.Fields)
+		case *ast.DirectiveDefinition:
+			addArgs(d.Arguments)
+		}
+	}
+
+	return names
+}
+
+// determines if a type is an introspection type
+func isIntrospectionType(name string) bool {
+	return strings.HasPrefix(name, "__")
+}
+
+// returns the names of a type map in sorted order
+func sortedTypeNames(typeMap graphql.TypeMap) []string {
+	names := []string{}
+	for name := range typeMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// creates an ast name
+func astName(name string) *ast.Name {
+	return ast.NewName(&ast.Name{Value: name})
+}
+
+// creates an ast description or nil if the description is empty
+func astDescription(description string) *ast.StringValue {
+	if description == "" {
+		return nil
+	}
+	return ast.NewStringValue(&ast.StringValue{Value: description})
+}
+
+// creates a deprecated directive list for a non empty deprecation reason
+func astDeprecated(reason string) []*ast.Directive {
+	if reason == "" {
+		return []*ast.Directive{}
+	}
+
+	directive := ast.NewDirective(&ast.Directive{
+		Name:      astName(graphql.DeprecatedDirective.Name),
+		Arguments: []*ast.Argument{},
+	})
+
+	if reason != graphql.DefaultDeprecationReason {
+		directive.Arguments = append(directive.Arguments, ast.NewArgument(&ast.Argument{
+			Name:  astName("reason"),
+			Value: ast.NewStringValue(&ast.StringValue{Value: reason}),
+		}))
+	}
+
+	return []*ast.Directive{directive}
+}
+
+// builds an ast type reference from a graphql type
+func astFromTypeRef(t graphql.Type) ast.Type {
+	switch tt := t.(type) {
+	case *graphql.NonNull:
+		return ast.NewNonNull(&ast.NonNull{Type: astFromTypeRef(tt.OfType)})
+	case *graphql.List:
+		return ast.NewList(&ast.List{Type: astFromTypeRef(tt.OfType)})
+	}
+	return ast.NewNamed(&ast.Named{Name: astName(t.Name())})
+}
+
+// builds an ast value from a go value using the input type to
+// determine how the value should be represented
+func astFromValue(value interface{}, t graphql.Type) ast.Value {
+	if isNullish(value) {
+		return nil
+	}
+
+	switch tt := t.(type) {
+	case *graphql.NonNull:
+		return astFromValue(value, tt.OfType)
+
+	case *graphql.List:
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return astFromValue(value, tt.OfType)
+		}
+		values := []ast.Value{}
+		for i := 0; i < v.Len(); i++ {
+			if item := astFromValue(v.Index(i).Interface(), tt.OfType); item != nil {
+				values = append(values, item)
+			}
+		}
+		return ast.NewListValue(&ast.ListValue{Values: values})
+
+	case *graphql.InputObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		fieldMap := tt.Fields()
+		names := []string{}
+		for name := range obj {
+			if _, ok := fieldMap[name]; ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		fields := []*ast.ObjectField{}
+		for _, name := range names {
+			if fieldValue := astFromValue(obj[name], fieldMap[name].Type); fieldValue != nil {
+				fields = append(fields, ast.NewObjectField(&ast.ObjectField{
+					Name:  astName(name),
+					Value: fieldValue,
+				}))
+			}
+		}
+		return ast.NewObjectValue(&ast.ObjectValue{Fields: fields})
+
+	case *graphql.Enum:
+		if name, ok := tt.Serialize(value).(string); ok {
+			return ast.NewEnumValue(&ast.EnumValue{Value: name})
+		}
+		return nil
+
+	case *graphql.Scalar:
+		serialized := tt.Serialize(value)
+		if isNullish(serialized) {
+			return nil
+		}
+		if tt.Name() == "ID" {
+			if s, ok := serialized.(string); ok {
+				if _, err := strconv.ParseInt(s, 10, 64); err == nil {
+					return ast.NewIntValue(&ast.IntValue{Value: s})
+				}
+			}
+		}
+		return astFromGoValue(serialized)
+	}
+
+	return nil
+}
+
+// builds an ast value from an untyped go value
+func astFromGoValue(value interface{}) ast.Value {
+	if isNullish(value) {
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		return astFromGoValue(v.Elem().Interface())
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		return ast.NewBooleanValue(&ast.BooleanValue{Value: v.Bool()})
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return ast.NewIntValue(&ast.IntValue{Value: strconv.FormatInt(v.Int(), 10)})
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return ast.NewIntValue(&ast.IntValue{Value: strconv.FormatUint(v.Uint(), 10)})
+	case reflect.Float32, reflect.Float64:
+		f := v.Float()
+		if f == math.Trunc(f) && math.Abs(f) < 1e21 {
+			return ast.NewIntValue(&ast.IntValue{Value: strconv.FormatFloat(f, 'f', -1, 64)})
+		}
+		return ast.NewFloatValue(&ast.FloatValue{Value: strconv.FormatFloat(f, 'g', -1, 64)})
+	case reflect.String:
+		return ast.NewStringValue(&ast.StringValue{Value: v.String()})
+	case reflect.Slice, reflect.Array:
+		values := []ast.Value{}
+		for i := 0; i < v.Len(); i++ {
+			if item := astFromGoValue(v.Index(i).Interface()); item != nil {
+				values = append(values, item)
+			}
+		}
+		return ast.NewListValue(&ast.ListValue{Values: values})
+	case reflect.Map:
+		keys := []string{}
+		for _, key := range v.MapKeys() {
+			keys = append(keys, fmt.Sprintf("%v", key.Interface()))
+		}
+		sort.Strings(keys)
+		fields := []*ast.ObjectField{}
+		for _, key := range keys {
+			item := v.MapIndex(reflect.ValueOf(key))
+			if !item.IsValid() {
+				continue
+			}
+			if fieldValue := astFromGoValue(item.Interface()); fieldValue != nil {
+				fields = append(fields, ast.NewObjectField(&ast.ObjectField{
+					Name:  astName(key),
+					Value: fieldValue,
+				}))
+			}
+		}
+		return ast.NewObjectValue(&ast.ObjectValue{Fields: fields})
+	}
+
+	return ast.NewStringValue(&ast.StringValue{Value: fmt.Sprintf("%v", value)})
+}
+
+// builds an ast definition from a named graphql type
+func astFromType(t graphql.Type) ast.Node {
+	switch tt := t.(type) {
+	case *graphql.Scalar:
+		return astFromScalar(tt)
+	case *graphql.Object:
+		return astFromObject(tt)
+	case *graphql.Interface:
+		return astFromInterface(tt)
+	case *graphql.Union:
+		return astFromUnion(tt)
+	case *graphql.Enum:
+		return astFromEnum(tt)
+	case *graphql.InputObject:
+		return astFromInputObject(tt)
+	}
+	return nil
+}
+
+// builds a scalar definition from a scalar type
+func astFromScalar(t *graphql.Scalar) *ast.ScalarDefinition {
+	return ast.NewScalarDefinition(&ast.ScalarDefinition{
+		Name:        astName(t.Name()),
+		Description: astDescription(t.Description()),
+		Directives:  []*ast.Directive{},
+	})
+}
+
+// builds an object definition from an object type
+func astFromObject(t *graphql.Object) *ast.ObjectDefinition {
+	ifaces := []*ast.Named{}
+	for _, iface := range t.Interfaces() {
+		ifaces = append(ifaces, ast.NewNamed(&ast.Named{Name: astName(iface.Name())}))
+	}
+	sort.Slice(ifaces, func(i, j int) bool {
+		return ifaces[i].Name.Value < ifaces[j].Name.Value
+	})
+
+	// Object.Description() always returns an empty string so the
+	// private description is used instead
+	return ast.NewObjectDefinition(&ast.ObjectDefinition{
+		Name:        astName(t.Name()),
+		Description: astDescription(t.PrivateDescription),
+		Interfaces:  ifaces,
+		Directives:  []*ast.Directive{},
+		Fields:      astFromFieldMap(t.Fields()),
+	})
+}
+
+// builds an interface definition from an interface type
+func astFromInterface(t *graphql.Interface) *ast.InterfaceDefinition {
+	return ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
+		Name:        astName(t.Name()),
+		Description: astDescription(t.Description()),
+		Directives:  []*ast.Directive{},
+		Fields:      astFromFieldMap(t.Fields()),
+	})
+}
+
+// builds a union definition from a union type
+func astFromUnion(t *graphql.Union) *ast.UnionDefinition {
+	types := []*ast.Named{}
+	for _, object := range t.Types() {
+		types = append(types, ast.NewNamed(&ast.Named{Name: astName(object.Name())}))
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i].Name.Value < types[j].Name.Value
+	})
+
+	return ast.NewUnionDefinition(&ast.UnionDefinition{
+		Name:        astName(t.Name()),
+		Description: astDescription(t.Description()),
+		Directives:  []*ast.Directive{},
+		Types:       types,
+	})
+}
+
+// builds an enum definition from an enum type
+func astFromEnum(t *graphql.Enum) *ast.EnumDefinition {
+	values := []*ast.EnumValueDefinition{}
+	for _, value := range t.Values() {
+		values = append(values, ast.NewEnumValueDefinition(&ast.EnumValueDefinition{
+			Name:        astName(value.Name),
+			Description: astDescription(value.Description),
+			Directives:  astDeprecated(value.DeprecationReason),
+		}))
+	}
+	sort.Slice(values, func(i, j int) bool {
+		return values[i].Name.Value < values[j].Name.Value
+	})
+
+	return ast.NewEnumDefinition(&ast.EnumDefinition{
+		Name:        astName(t.Name()),
+		Description: astDescription(t.Description()),
+		Directives:  []*ast.Directive{},
+		Values:      values,
+	})
+}
+
+// builds an input object definition from an input object type
+func astFromInputObject(t *graphql.InputObject) *ast.InputObjectDefinition {
+	fieldMap := t.Fields()
+	names := []string{}
+	for name := range fieldMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := []*ast.InputValueDefinition{}
+	for _, name := range names {
+		field := fieldMap[name]
+		fields = append(fields, ast.NewInputValueDefinition(&ast.InputValueDefinition{
+			Name:         astName(name),
+			Description:  astDescription(field.Description()),
+			Type:         astFromTypeRef(field.Type),
+			DefaultValue: astFromValue(field.DefaultValue, field.Type),
+			Directives:   []*ast.Directive{},
+		}))
+	}
+
+	return ast.NewInputObjectDefinition(&ast.InputObjectDefinition{
+		Name:        astName(t.Name()),
+		Description: astDescription(t.Description()),
+		Directives:  []*ast.Directive{},
+		Fields:      fields,
+	})
+}
+
+// builds a list of field definitions sorted by name from a field map
+func astFromFieldMap(fieldMap graphql.FieldDefinitionMap) []*ast.FieldDefinition {
+	names := []string{}
+	for name := range fieldMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fields := []*ast.FieldDefinition{}
+	for _, name := range names {
+		fields = append(fields, astFromField(fieldMap[name]))
+	}
+	return fields
+}
+
+// builds a field definition from a field
+func astFromField(field *graphql.FieldDefinition) *ast.FieldDefinition {
+	return ast.NewFieldDefinition(&ast.FieldDefinition{
+		Name:        astName(field.Name),
+		Description: astDescription(field.Description),
+		Arguments:   astFromArgs(field.Args),
+		Type:        astFromTypeRef(field.Type),
+		Directives:  astDeprecated(field.DeprecationReason),
+	})
+}
+
+// builds a list of input value definitions sorted by name from arguments
+func astFromArgs(args []*graphql.Argument) []*ast.InputValueDefinition {
+	sorted := append([]*graphql.Argument{}, args...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name() < sorted[j].Name()
+	})
+
+	defs := []*ast.InputValueDefinition{}
+	for _, arg := range sorted {
+		defs = append(defs, ast.NewInputValueDefinition(&ast.InputValueDefinition{
+			Name:         astName(arg.Name()),
+			Description:  astDescription(arg.Description()),
+			Type:         astFromTypeRef(arg.Type),
+			DefaultValue: astFromValue(arg.DefaultValue, arg.Type),
+			Directives:   []*ast.Directive{},
+		}))
+	}
+	return defs
+}
+
+// builds a directive definition from a directive
+func astFromDirective(directive *graphql.Directive) *ast.DirectiveDefinition {
+	locations := []*ast.Name{}
+	for _, loc := range directive.Locations {
+		locations = append(locations, astName(loc))
+	}
+
+	return ast.NewDirectiveDefinition(&ast.DirectiveDefinition{
+		Name:        astName(directive.Name),
+		Description: astDescription(directive.Description),
+		Arguments:   astFromArgs(directive.Args),
+		Locations:   locations,
+	})
+}
@@ -0,0 +1,151 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+)
+
+const directiveAuth = "auth"
+
+// AuthDirective requires the principal of a request to be authorized to resolve
+// the fields of an object or interface or a single field
+var AuthDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:        directiveAuth,
+	Description: "Requires the principal to have one of the roles to resolve the field",
+	Locations: []string{
+		graphql.DirectiveLocationObject,
+		graphql.DirectiveLocationInterface,
+		graphql.DirectiveLocationFieldDefinition,
+	},
+	Args: graphql.FieldConfigArgument{
+		"requires": &graphql.ArgumentConfig{
+			Type:        graphql.NewList(graphql.String),
+			Description: "The roles allowed to resolve the field, any authenticated principal when empty",
+		},
+	},
+})
+
+// AuthError an authorization error. The code is added to the extensions of the graphql error
+type AuthError struct {
+	Message string
+	Code    string
+}
+
+func (e *AuthError) Error() string {
+	return e.Message
+}
+
+// Extensions returns the error extensions
+func (e *AuthError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// Authorization errors
+var (
+	ErrUnauthenticated = &AuthError{
+		Message: "not authenticated",
+		Code:    "UNAUTHENTICATED",
+	}
+	ErrForbidden = &AuthError{
+		Message: "not authorized",
+		Code:    "FORBIDDEN",
+	}
+)
+
+// Authorizer determines if the principal in the context of a request can resolve
+// a field that requires the roles. The context is the one supplied by the server
+// ContextFunc or the websocket AuthenticateFunc
+type Authorizer interface {
+	Authorize(ctx context.Context, requires []string, info graphql.ResolveInfo) error
+}
+
+// AuthorizerFunc an Authorizer function
+type AuthorizerFunc func(ctx context.Context, requires []string, info graphql.ResolveInfo) error
+
+// Authorize calls the function
+func (f AuthorizerFunc) Authorize(ctx context.Context, requires []string, info graphql.ResolveInfo) error {
+	return f(ctx, requires, info)
+}
+
+// PrincipalRolesFunc gets the roles of the principal in a context, ok is false
+// when the request is not authenticated
+type PrincipalRolesFunc func(ctx context.Context) (roles []string, ok bool)
+
+// NewRoleAuthorizer creates an Authorizer that allows principals with any of the
+// required roles. Directives without roles only require an authenticated principal
+func NewRoleAuthorizer(rolesFn PrincipalRolesFunc) Authorizer {
+	return AuthorizerFunc(func(ctx context.Context, requires []string, info graphql.ResolveInfo) error {
+		if ctx == nil {
+			return ErrUnauthenticated
+		}
+
+		roles, ok := rolesFn(ctx)
+		if !ok {
+			return ErrUnauthenticated
+		}
+		if len(requires) == 0 {
+			return nil
+		}
+
+		for _, role := range roles {
+			for _, required := range requires {
+				if role == required {
+					return nil
+				}
+			}
+		}
+		return ErrForbidden
+	})
+}
+
+// NewAuthDirectiveVisitor creates a visitor for the @auth directive that calls the
+// authorizer before resolving a field. Directives on objects and interfaces apply
+// to all of their fields and a directive on a field overrides them. The directive
+// can also be declared with an enum for the requires argument
+func NewAuthDirectiveVisitor(authorizer Authorizer) *SchemaDirectiveVisitor {
+	return &SchemaDirectiveVisitor{
+		ApplyToFields: true,
+		VisitFieldDefinition: func(p VisitFieldDefinitionParams) error {
+			requires, err := authRequires(p.Args)
+			if err != nil {
+				return fmt.Errorf("invalid @auth on %s.%s: %v", p.ParentName, p.Config.Name, err)
+			}
+
+			resolve := p.Config.Resolve
+			if resolve == nil {
+				resolve = graphql.DefaultResolveFn
+			}
+
+			p.Config.Resolve = func(rp graphql.ResolveParams) (interface{}, error) {
+				if err := authorizer.Authorize(rp.Context, requires, rp.Info); err != nil {
+					return nil, err
+				}
+				return resolve(rp)
+			}
+			return nil
+		},
+	}
+}
+
+// gets the required roles from the directive arguments. Values that could not
+// be coerced are rejected so that a typo never removes a requirement
+func authRequires(args map[string]interface{}) ([]string, error) {
+	requires := []string{}
+
+	switch value := args["requires"].(type) {
+	case nil:
+	case []interface{}:
+		for _, role := range value {
+			if role == nil {
+				return nil, fmt.Errorf("requires must be a list of strings or enum values of the declared type")
+			}
+			requires = append(requires, fmt.Sprintf("%v", role))
+		}
+	default:
+		requires = append(requires, fmt.Sprintf("%v", value))
+	}
+
+	return requires, nil
+}
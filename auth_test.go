@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type authRolesKey struct{}
+
+func TestAuthDirective(t *testing.T) {
+	typeDefs := `
+enum Role {
+	ADMIN
+	USER
+}
+
+directive @auth(requires: [Role]) on OBJECT | INTERFACE | FIELD_DEFINITION
+
+interface Node {
+	id: ID!
+	secret: String @auth(requires: [ADMIN])
+}
+
+type User implements Node @auth {
+	id: ID!
+	name: String
+	secret: String
+	salary: Int @auth(requires: [ADMIN])
+}
+
+type Query {
+	me: User
+	version: String
+}
+`
+
+	authorizer := NewRoleAuthorizer(func(ctx context.Context) ([]string, bool) {
+		roles, ok := ctx.Value(authRolesKey{}).([]string)
+		return roles, ok
+	})
+
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: typeDefs,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"me": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return map[string]interface{}{
+								"id":     "1",
+								"name":   "bob",
+								"secret": "shh",
+								"salary": 100,
+							}, nil
+						},
+					},
+					"version": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return "1", nil
+						},
+					},
+				},
+			},
+		},
+		SchemaDirectives: SchemaDirectiveVisitorMap{
+			"auth": NewAuthDirectiveVisitor(authorizer),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		query  string
+		errors int
+		code   string
+	}{
+		{"public field", context.Background(), `{ version }`, 0, ""},
+		{"unauthenticated", context.Background(), `{ me { name } }`, 1, "UNAUTHENTICATED"},
+		{"authenticated", context.WithValue(context.Background(), authRolesKey{}, []string{"USER"}), `{ me { id name } }`, 0, ""},
+		{"field role", context.WithValue(context.Background(), authRolesKey{}, []string{"USER"}), `{ me { salary } }`, 1, "FORBIDDEN"},
+		{"interface field role", context.WithValue(context.Background(), authRolesKey{}, []string{"USER"}), `{ me { secret } }`, 1, "FORBIDDEN"},
+		{"admin", context.WithValue(context.Background(), authRolesKey{}, []string{"ADMIN"}), `{ me { name salary secret } }`, 0, ""},
+	}
+
+	for _, test := range tests {
+		r := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: test.query,
+			Context:       test.ctx,
+		})
+
+		if len(r.Errors) != test.errors {
+			t.Errorf("%s: expected %d errors, got %v", test.name, test.errors, r.Errors)
+			continue
+		}
+		if test.code != "" {
+			if code := r.Errors[0].Extensions["code"]; code != test.code {
+				t.Errorf("%s: expected code %s, got %v", test.name, test.code, code)
+			}
+		}
+	}
+}
+
+func TestAuthDirectiveBuiltin(t *testing.T) {
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+type Query {
+	admin: String @auth(requires: ["ADMIN"])
+}`,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"admin": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return "ok", nil
+						},
+					},
+				},
+			},
+		},
+		SchemaDirectives: SchemaDirectiveVisitorMap{
+			"auth": NewAuthDirectiveVisitor(AuthorizerFunc(func(ctx context.Context, requires []string, info graphql.ResolveInfo) error {
+				if len(requires) != 1 || requires[0] != "ADMIN" || info.FieldName != "admin" {
+					t.Errorf("unexpected authorize call %v %s", requires, info.FieldName)
+				}
+				return ErrForbidden
+			})),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ admin }`,
+	})
+	if len(r.Errors) != 1 {
+		t.Errorf("expected a forbidden error, got %v", r.Errors)
+	}
+}
@@ -0,0 +1,225 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const bindTagName = "graphql"
+
+var (
+	bindContextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	bindParamsType  = reflect.TypeOf(graphql.ResolveParams{})
+	bindInfoType    = reflect.TypeOf(graphql.ResolveInfo{})
+	bindErrorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// binds the exported methods and tagged func fields of a go struct to the
+// fields of the object type with the same name. Func fields are bound by
+// their graphql tag and methods by a case insensitive match of the field name.
+//
+// Bound functions can accept a context.Context, graphql.ResolveParams, and
+// graphql.ResolveInfo in any order, as well as up to two structs or maps. If
+// there is a single struct or map it receives the field args, if there are two
+// the first receives the source and the second receives the field args.
+// Bound functions return a value, an error, or a value and an error
+func (c *registry) bindResolver(name string, resolver interface{}) (*ObjectResolver, error) {
+	value := reflect.ValueOf(resolver)
+	structValue := reflect.Indirect(value)
+	if !value.IsValid() || structValue.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("invalid resolver type for %s", name)
+	}
+
+	fieldNames := c.getObjectFieldNames(name)
+	if len(fieldNames) == 0 {
+		return nil, fmt.Errorf("failed to bind resolver %s: no object definition found", name)
+	}
+
+	bindings := map[string]reflect.Value{}
+
+	// tagged func fields take precedence over methods
+	structType := structValue.Type()
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := strings.Split(field.Tag.Get(bindTagName), ",")[0]
+		if tag == "" || tag == "-" || field.PkgPath != "" || field.Type.Kind() != reflect.Func {
+			continue
+		}
+		if fn := structValue.Field(i); !fn.IsNil() {
+			bindings[tag] = fn
+		}
+	}
+
+	for i := 0; i < value.NumMethod(); i++ {
+		method := value.Type().Method(i)
+		for _, fieldName := range fieldNames {
+			if _, ok := bindings[fieldName]; !ok && strings.EqualFold(method.Name, fieldName) {
+				bindings[fieldName] = value.Method(i)
+			}
+		}
+	}
+
+	fields := FieldResolveMap{}
+	for fieldName, fn := range bindings {
+		resolve, err := bindResolveFn(fn)
+		if err != nil {
+			return nil, fmt.Errorf("failed to bind %s.%s: %v", name, fieldName, err)
+		}
+		fields[fieldName] = &FieldResolve{Resolve: resolve}
+	}
+
+	return &ObjectResolver{Fields: fields}, nil
+}
+
+// gets the field names of an object and its extensions from the document
+func (c *registry) getObjectFieldNames(name string) []string {
+	names := []string{}
+	addFields := func(def *ast.ObjectDefinition) {
+		if def.Name.Value != name {
+			return
+		}
+		for _, field := range def.Fields {
+			names = append(names, field.Name.Value)
+		}
+	}
+
+	for _, def := range c.document.Definitions {
+		switch node := def.(type) {
+		case *ast.ObjectDefinition:
+			addFields(node)
+		case *ast.TypeExtensionDefinition:
+			addFields(node.Definition)
+		}
+	}
+	return names
+}
+
+// creates a field resolve function that calls a go function
+func bindResolveFn(fn reflect.Value) (graphql.FieldResolveFn, error) {
+	fnType := fn.Type()
+	if fnType.IsVariadic() {
+		return nil, fmt.Errorf("variadic functions are not supported")
+	}
+
+	// validate the return values
+	switch fnType.NumOut() {
+	case 1:
+	case 2:
+		if fnType.Out(1) != bindErrorType {
+			return nil, fmt.Errorf("second return value must be an error")
+		}
+	default:
+		return nil, fmt.Errorf("must return a value, an error, or a value and an error")
+	}
+
+	// identify the source and args parameters
+	valueParams := []int{}
+	for i := 0; i < fnType.NumIn(); i++ {
+		switch in := fnType.In(i); in {
+		case bindContextType, bindParamsType, bindInfoType:
+		default:
+			if !isBindableValue(in) {
+				return nil, fmt.Errorf("unsupported parameter type %s", in)
+			}
+			valueParams = append(valueParams, i)
+		}
+	}
+	if len(valueParams) > 2 {
+		return nil, fmt.Errorf("too many parameters, expected at most a source and args")
+	}
+
+	sourceParam, argsParam := -1, -1
+	switch len(valueParams) {
+	case 1:
+		argsParam = valueParams[0]
+	case 2:
+		sourceParam, argsParam = valueParams[0], valueParams[1]
+	}
+
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		in := make([]reflect.Value, fnType.NumIn())
+		for i := range in {
+			switch paramType := fnType.In(i); {
+			case paramType == bindContextType:
+				ctx := p.Context
+				if ctx == nil {
+					ctx = context.Background()
+				}
+				in[i] = reflect.ValueOf(&ctx).Elem()
+			case paramType == bindParamsType:
+				in[i] = reflect.ValueOf(p)
+			case paramType == bindInfoType:
+				in[i] = reflect.ValueOf(p.Info)
+			case i == sourceParam:
+				source, err := bindValue(p.Source, paramType)
+				if err != nil {
+					return nil, fmt.Errorf("failed to bind source: %v", err)
+				}
+				in[i] = source
+			case i == argsParam:
+				args, err := bindValue(p.Args, paramType)
+				if err != nil {
+					return nil, fmt.Errorf("failed to bind args: %v", err)
+				}
+				in[i] = args
+			}
+		}
+
+		out := fn.Call(in)
+		if len(out) == 1 && fnType.Out(0) == bindErrorType {
+			return nil, errorValue(out[0])
+		}
+		if len(out) == 2 {
+			return out[0].Interface(), errorValue(out[1])
+		}
+		return out[0].Interface(), nil
+	}, nil
+}
+
+// determines if a parameter type can receive a source or args
+func isBindableValue(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Map:
+		return true
+	}
+	return false
+}
+
+// converts a value to the parameter type using the value directly
+// if it is assignable or a json round trip if it is not
+func bindValue(value interface{}, t reflect.Type) (reflect.Value, error) {
+	if value != nil {
+		if v := reflect.ValueOf(value); v.Type().AssignableTo(t) {
+			return v, nil
+		}
+	}
+
+	ptr := reflect.New(t)
+	if value != nil {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if err := json.Unmarshal(data, ptr.Interface()); err != nil {
+			return reflect.Value{}, err
+		}
+	}
+	return ptr.Elem(), nil
+}
+
+// gets an error from a reflected error value
+func errorValue(v reflect.Value) error {
+	if v.IsNil() {
+		return nil
+	}
+	return v.Interface().(error)
+}
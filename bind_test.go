@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+type bindUser struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bindUserArgs struct {
+	ID string `json:"id"`
+}
+
+type bindGreetingArgs struct {
+	Greeting string `json:"greeting"`
+}
+
+type bindQuery struct {
+	Version func() string `graphql:"apiVersion"`
+	users   map[string]*bindUser
+}
+
+func (q *bindQuery) User(ctx context.Context, args bindUserArgs) (*bindUser, error) {
+	if ctx.Value(bindContextKey("user")) != "admin" {
+		return nil, errors.New("unauthorized")
+	}
+	return q.users[args.ID], nil
+}
+
+func (q *bindQuery) Helper() string {
+	return "not a field"
+}
+
+type bindUserResolvers struct{}
+
+func (r bindUserResolvers) Greet(source bindUser, args *bindGreetingArgs) string {
+	return args.Greeting + " " + source.Name
+}
+
+type bindContextKey string
+
+func TestBindResolvers(t *testing.T) {
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+type User {
+	id: ID!
+	name: String
+	greet(greeting: String = "Hello"): String
+}
+
+type Query {
+	apiVersion: String
+	user(id: ID!): User
+}`,
+		Resolvers: ResolverMap{
+			"Query": &bindQuery{
+				Version: func() string { return "1.0" },
+				users: map[string]*bindUser{
+					"1": {ID: "1", Name: "Jane"},
+				},
+			},
+			"User": bindUserResolvers{},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema:        schema,
+		Context:       context.WithValue(context.Background(), bindContextKey("user"), "admin"),
+		RequestString: `{ apiVersion user(id: "1") { id name greet hi: greet(greeting: "Hi") } }`,
+	})
+	if r.HasErrors() {
+		t.Error(r.Errors)
+		return
+	}
+
+	data, _ := json.Marshal(r.Data)
+	expected := `{"apiVersion":"1.0","user":{"greet":"Hello Jane","hi":"Hi Jane","id":"1","name":"Jane"}}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+		return
+	}
+
+	r = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ user(id: "1") { id } }`,
+	})
+	if !r.HasErrors() || r.Errors[0].Message != "unauthorized" {
+		t.Errorf("expected unauthorized error, got %v", r.Errors)
+		return
+	}
+}
+
+func TestBindResolversInvalid(t *testing.T) {
+	typeDefs := `
+type Query {
+	foo: String
+}`
+
+	if _, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs:  typeDefs,
+		Resolvers: ResolverMap{"Query": "foo"},
+	}); err == nil {
+		t.Error("expected error for non-struct resolver")
+		return
+	}
+
+	if _, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: typeDefs,
+		Resolvers: ResolverMap{
+			"Query": &struct {
+				Foo func(a, b, c map[string]interface{}) string `graphql:"foo"`
+			}{
+				Foo: func(a, b, c map[string]interface{}) string { return "" },
+			},
+		},
+	}); err == nil {
+		t.Error("expected error for unsupported signature")
+		return
+	}
+}
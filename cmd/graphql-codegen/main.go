@@ -0,0 +1,103 @@
+// Command graphql-codegen generates typed go models and resolver interfaces from graphql type definitions
+//
+// Usage:
+//
+//	graphql-codegen -schema ./schema -recursive -package models -out ./models/generated.go
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	tools "github.com/bhoriuchi/graphql-go-tools"
+	"github.com/bhoriuchi/graphql-go-tools/codegen"
+)
+
+// a repeatable flag of name=value pairs
+type mapFlag map[string]string
+
+func (m mapFlag) String() string {
+	pairs := []string{}
+	for key, value := range m {
+		pairs = append(pairs, key+"="+value)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (m mapFlag) Set(value string) error {
+	parts := strings.SplitN(value, "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("expected name=type, got %q", value)
+	}
+	m[parts[0]] = parts[1]
+	return nil
+}
+
+// a repeatable string flag
+type listFlag []string
+
+func (l *listFlag) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *listFlag) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func main() {
+	scalars := mapFlag{}
+	imports := listFlag{}
+
+	schema := flag.String("schema", ".", "graphql file or directory of .graphql/.gql files")
+	recursive := flag.Bool("recursive", false, "read schema directories recursively")
+	pkg := flag.String("package", "models", "package name of the generated code")
+	out := flag.String("out", "", "output file, defaults to stdout")
+	flag.Var(scalars, "scalar", "go type for a custom scalar as name=type, can be repeated")
+	flag.Var(&imports, "import", "additional import for custom scalar types, can be repeated")
+	flag.Parse()
+
+	if err := run(*schema, *recursive, *pkg, *out, scalars, imports); err != nil {
+		fmt.Fprintf(os.Stderr, "graphql-codegen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(schema string, recursive bool, pkg, out string, scalars map[string]string, imports []string) error {
+	info, err := os.Stat(schema)
+	if err != nil {
+		return err
+	}
+
+	var typeDefs string
+	if info.IsDir() {
+		if typeDefs, err = tools.ReadSourceFiles(schema, recursive); err != nil {
+			return err
+		}
+	} else {
+		data, err := ioutil.ReadFile(schema)
+		if err != nil {
+			return err
+		}
+		typeDefs = string(data)
+	}
+
+	src, err := codegen.Generate(codegen.Config{
+		TypeDefs: typeDefs,
+		Package:  pkg,
+		Scalars:  scalars,
+		Imports:  imports,
+	})
+	if err != nil {
+		return err
+	}
+
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
@@ -0,0 +1,591 @@
+// Package codegen generates typed go models and resolver interfaces from graphql type definitions
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	tools "github.com/bhoriuchi/graphql-go-tools"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	toolsImport   = "github.com/bhoriuchi/graphql-go-tools"
+	graphqlImport = "github.com/graphql-go/graphql"
+)
+
+// Config code generation configuration
+type Config struct {
+	TypeDefs interface{}       // any value supported by tools.ExecutableSchema TypeDefs
+	Package  string            // package name of the generated code, defaults to models
+	Scalars  map[string]string // go types for custom scalars, e.g. {"JSON": "map[string]interface{}"}, defaults to interface{}
+	Imports  []string          // additional imports required by the custom scalar go types
+}
+
+// Generate generates go structs for object and input types, typed enums,
+// typed resolver interfaces and a function that converts an implementation
+// of the resolver interfaces into a tools.ResolverMap
+func Generate(config Config) ([]byte, error) {
+	es := tools.ExecutableSchema{TypeDefs: config.TypeDefs}
+	document, err := es.ConcatenateTypeDefs()
+	if err != nil {
+		return nil, err
+	}
+
+	if config.Package == "" {
+		config.Package = "models"
+	}
+
+	g := newGenerator(config)
+	if err := g.load(document); err != nil {
+		return nil, err
+	}
+
+	src := g.generate()
+	formatted, err := format.Source(src)
+	if err != nil {
+		return src, fmt.Errorf("failed to format generated code: %v", err)
+	}
+	return formatted, nil
+}
+
+type generator struct {
+	config     Config
+	imports    map[string]bool
+	roots      map[string]string
+	objects    map[string]*ast.ObjectDefinition
+	interfaces map[string]*ast.InterfaceDefinition
+	unions     map[string]*ast.UnionDefinition
+	enums      map[string]*ast.EnumDefinition
+	inputs     map[string]*ast.InputObjectDefinition
+	scalars    map[string]bool
+	abstracts  map[string][]string // object name to the interfaces and unions it belongs to
+	buf        *bytes.Buffer
+}
+
+func newGenerator(config Config) *generator {
+	return &generator{
+		config:     config,
+		imports:    map[string]bool{},
+		roots:      map[string]string{},
+		objects:    map[string]*ast.ObjectDefinition{},
+		interfaces: map[string]*ast.InterfaceDefinition{},
+		unions:     map[string]*ast.UnionDefinition{},
+		enums:      map[string]*ast.EnumDefinition{},
+		inputs:     map[string]*ast.InputObjectDefinition{},
+		scalars:    map[string]bool{},
+		abstracts:  map[string][]string{},
+		buf:        &bytes.Buffer{},
+	}
+}
+
+// loads the definitions of the document merging any extensions
+func (g *generator) load(document *ast.Document) error {
+	extensions := []ast.Node{}
+	for _, def := range document.Definitions {
+		switch node := def.(type) {
+		case *ast.SchemaDefinition:
+			for _, op := range node.OperationTypes {
+				g.roots[op.Type.Name.Value] = op.Operation
+			}
+		case *ast.ObjectDefinition:
+			g.objects[node.Name.Value] = node
+		case *ast.InterfaceDefinition:
+			g.interfaces[node.Name.Value] = node
+		case *ast.UnionDefinition:
+			g.unions[node.Name.Value] = node
+		case *ast.EnumDefinition:
+			g.enums[node.Name.Value] = node
+		case *ast.InputObjectDefinition:
+			g.inputs[node.Name.Value] = node
+		case *ast.ScalarDefinition:
+			g.scalars[node.Name.Value] = true
+		default:
+			extensions = append(extensions, def)
+		}
+	}
+
+	for _, def := range extensions {
+		switch node := def.(type) {
+		case *ast.TypeExtensionDefinition:
+			obj, ok := g.objects[node.Definition.Name.Value]
+			if !ok {
+				return fmt.Errorf("cannot extend undefined type %q", node.Definition.Name.Value)
+			}
+			g.objects[obj.Name.Value] = tools.MergeExtensions(obj, node.Definition)
+		case *tools.InterfaceExtensionDefinition:
+			iface, ok := g.interfaces[node.Definition.Name.Value]
+			if !ok {
+				return fmt.Errorf("cannot extend undefined interface %q", node.Definition.Name.Value)
+			}
+			merged := *iface
+			merged.Fields = append(append([]*ast.FieldDefinition{}, iface.Fields...), node.Definition.Fields...)
+			g.interfaces[iface.Name.Value] = &merged
+		case *tools.UnionExtensionDefinition:
+			union, ok := g.unions[node.Definition.Name.Value]
+			if !ok {
+				return fmt.Errorf("cannot extend undefined union %q", node.Definition.Name.Value)
+			}
+			merged := *union
+			merged.Types = append(append([]*ast.Named{}, union.Types...), node.Definition.Types...)
+			g.unions[union.Name.Value] = &merged
+		case *tools.EnumExtensionDefinition:
+			enum, ok := g.enums[node.Definition.Name.Value]
+			if !ok {
+				return fmt.Errorf("cannot extend undefined enum %q", node.Definition.Name.Value)
+			}
+			merged := *enum
+			merged.Values = append(append([]*ast.EnumValueDefinition{}, enum.Values...), node.Definition.Values...)
+			g.enums[enum.Name.Value] = &merged
+		case *tools.InputObjectExtensionDefinition:
+			input, ok := g.inputs[node.Definition.Name.Value]
+			if !ok {
+				return fmt.Errorf("cannot extend undefined input %q", node.Definition.Name.Value)
+			}
+			merged := *input
+			merged.Fields = append(append([]*ast.InputValueDefinition{}, input.Fields...), node.Definition.Fields...)
+			g.inputs[input.Name.Value] = &merged
+		}
+	}
+
+	// use the default root names if there is no schema definition
+	if len(g.roots) == 0 {
+		g.roots[tools.DefaultRootQueryName] = ast.OperationTypeQuery
+		g.roots[tools.DefaultRootMutationName] = ast.OperationTypeMutation
+		g.roots[tools.DefaultRootSubscriptionName] = ast.OperationTypeSubscription
+	}
+
+	// map each object to the abstract types it belongs to
+	for _, name := range sortedKeys(g.objects) {
+		seen := map[string]bool{}
+		for _, iface := range g.objects[name].Interfaces {
+			if !seen[iface.Name.Value] {
+				seen[iface.Name.Value] = true
+				g.abstracts[name] = append(g.abstracts[name], iface.Name.Value)
+			}
+		}
+	}
+	for _, name := range sortedKeys(g.unions) {
+		for _, member := range g.unions[name].Types {
+			g.abstracts[member.Name.Value] = append(g.abstracts[member.Name.Value], name)
+		}
+	}
+
+	return nil
+}
+
+// generates the unformatted source
+func (g *generator) generate() []byte {
+	body := &bytes.Buffer{}
+	g.buf = body
+	g.imports[toolsImport] = true
+	g.imports[graphqlImport] = true
+
+	g.generateEnums()
+	g.generateAbstractTypes()
+	g.generateObjects()
+	g.generateInputs()
+	g.generateArgs()
+	g.generateResolvers()
+	g.generateResolverMap()
+
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by graphql-go-tools codegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(out, "package %s\n\n", g.config.Package)
+
+	// group the standard library imports before all other imports
+	std, other := []string{}, []string{}
+	for _, imp := range g.config.Imports {
+		g.imports[imp] = true
+	}
+	for imp := range g.imports {
+		if strings.Contains(strings.Split(imp, "/")[0], ".") {
+			other = append(other, imp)
+		} else {
+			std = append(std, imp)
+		}
+	}
+	sort.Strings(std)
+	sort.Strings(other)
+
+	out.WriteString("import (\n")
+	for _, imp := range std {
+		fmt.Fprintf(out, "%q\n", imp)
+	}
+	out.WriteString("\n")
+	for _, imp := range other {
+		if imp == toolsImport {
+			fmt.Fprintf(out, "tools %q\n", imp)
+		} else {
+			fmt.Fprintf(out, "%q\n", imp)
+		}
+	}
+	out.WriteString(")\n\n")
+	out.Write(body.Bytes())
+	return out.Bytes()
+}
+
+func (g *generator) printf(format string, a ...interface{}) {
+	fmt.Fprintf(g.buf, format, a...)
+}
+
+// writes a doc comment from a description or a default comment
+func (g *generator) comment(name string, description *ast.StringValue, fallback string) {
+	if description == nil || strings.TrimSpace(description.Value) == "" {
+		g.printf("// %s %s\n", name, fallback)
+		return
+	}
+	lines := strings.Split(strings.TrimSpace(description.Value), "\n")
+	g.printf("// %s %s\n", name, strings.TrimSpace(lines[0]))
+	for _, line := range lines[1:] {
+		g.printf("// %s\n", strings.TrimSpace(line))
+	}
+}
+
+func (g *generator) generateEnums() {
+	for _, name := range sortedKeys(g.enums) {
+		enum := g.enums[name]
+		typeName := goName(name)
+		g.comment(typeName, enum.Description, "enum")
+		g.printf("type %s string\n\n", typeName)
+		g.printf("// %s values\n", typeName)
+		g.printf("const (\n")
+		for _, value := range enum.Values {
+			g.printf("%s %s = %q\n", typeName+goName(value.Name.Value), typeName, value.Name.Value)
+		}
+		g.printf(")\n\n")
+	}
+}
+
+func (g *generator) generateAbstractTypes() {
+	abstracts := map[string]*ast.StringValue{}
+	for name, iface := range g.interfaces {
+		abstracts[name] = iface.Description
+	}
+	for name, union := range g.unions {
+		abstracts[name] = union.Description
+	}
+
+	for _, name := range sortedKeys(abstracts) {
+		typeName := goName(name)
+		g.comment(typeName, abstracts[name], "is implemented by the types it can resolve to")
+		g.printf("type %s interface {\nIs%s()\n}\n\n", typeName, typeName)
+	}
+}
+
+func (g *generator) generateObjects() {
+	for _, name := range sortedKeys(g.objects) {
+		if _, isRoot := g.roots[name]; isRoot {
+			continue
+		}
+
+		object := g.objects[name]
+		typeName := goName(name)
+		g.comment(typeName, object.Description, "object")
+		g.printf("type %s struct {\n", typeName)
+		seen := map[string]bool{}
+		for _, field := range object.Fields {
+			// fields with arguments are resolved by the type resolver
+			if seen[field.Name.Value] || len(field.Arguments) > 0 {
+				continue
+			}
+			seen[field.Name.Value] = true
+			g.printf("%s %s `json:%q`\n", goName(field.Name.Value), g.goType(field.Type, true), field.Name.Value)
+		}
+		g.printf("}\n\n")
+
+		for _, abstract := range g.abstracts[name] {
+			g.printf("// Is%s implements %s\n", goName(abstract), goName(abstract))
+			g.printf("func (*%s) Is%s() {}\n\n", typeName, goName(abstract))
+		}
+	}
+}
+
+func (g *generator) generateInputs() {
+	for _, name := range sortedKeys(g.inputs) {
+		input := g.inputs[name]
+		typeName := goName(name)
+		g.comment(typeName, input.Description, "input")
+		g.printf("type %s struct {\n", typeName)
+		g.inputFields(input.Fields)
+		g.printf("}\n\n")
+	}
+}
+
+func (g *generator) inputFields(fields []*ast.InputValueDefinition) {
+	seen := map[string]bool{}
+	for _, field := range fields {
+		if seen[field.Name.Value] {
+			continue
+		}
+		seen[field.Name.Value] = true
+		g.printf("%s %s `json:%q`\n", goName(field.Name.Value), g.goType(field.Type, true), field.Name.Value)
+	}
+}
+
+func (g *generator) generateArgs() {
+	for _, name := range sortedKeys(g.objects) {
+		for _, field := range g.objects[name].Fields {
+			if len(field.Arguments) == 0 {
+				continue
+			}
+			argsName := argsTypeName(name, field.Name.Value)
+			g.printf("// %s arguments for %s.%s\n", argsName, name, field.Name.Value)
+			g.printf("type %s struct {\n", argsName)
+			g.inputFields(field.Arguments)
+			g.printf("}\n\n")
+		}
+	}
+}
+
+// gets the fields of an object that require a resolver
+func (g *generator) resolverFields(name string) []*ast.FieldDefinition {
+	_, isRoot := g.roots[name]
+	fields := []*ast.FieldDefinition{}
+	seen := map[string]bool{}
+	for _, field := range g.objects[name].Fields {
+		if seen[field.Name.Value] {
+			continue
+		}
+		seen[field.Name.Value] = true
+		if isRoot || len(field.Arguments) > 0 {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// gets the names of the objects that require a resolver
+func (g *generator) resolverTypes() []string {
+	names := []string{}
+	for _, name := range sortedKeys(g.objects) {
+		if len(g.resolverFields(name)) > 0 {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (g *generator) generateResolvers() {
+	g.imports["context"] = true
+
+	for _, name := range g.resolverTypes() {
+		typeName := goName(name)
+		operation, isRoot := g.roots[name]
+		g.printf("// %sResolver resolves the fields of %s\n", typeName, name)
+		g.printf("type %sResolver interface {\n", typeName)
+		for _, field := range g.resolverFields(name) {
+			params := []string{"ctx context.Context"}
+			if !isRoot {
+				params = append(params, "obj *"+typeName)
+			}
+			if len(field.Arguments) > 0 {
+				params = append(params, "args "+argsTypeName(name, field.Name.Value))
+			}
+
+			returnType := g.goType(field.Type, true)
+			if isRoot && operation == ast.OperationTypeSubscription {
+				returnType = "chan interface{}"
+			}
+			g.printf("%s(%s) (%s, error)\n", goName(field.Name.Value), strings.Join(params, ", "), returnType)
+		}
+		g.printf("}\n\n")
+	}
+
+	g.printf("// ResolverRoot provides the resolvers for each type\n")
+	g.printf("type ResolverRoot interface {\n")
+	for _, name := range g.resolverTypes() {
+		g.printf("%s() %sResolver\n", goName(name), goName(name))
+	}
+	g.printf("}\n\n")
+}
+
+func (g *generator) generateResolverMap() {
+	g.printf("// NewResolverMap creates a tools.ResolverMap from a ResolverRoot\n")
+	g.printf("func NewResolverMap(r ResolverRoot) tools.ResolverMap {\n")
+	g.printf("return tools.ResolverMap{\n")
+
+	for _, name := range sortedKeys(g.enums) {
+		typeName := goName(name)
+		g.printf("%q: &tools.EnumResolver{\nValues: map[string]interface{}{\n", name)
+		for _, value := range g.enums[name].Values {
+			g.printf("%q: %s,\n", value.Name.Value, typeName+goName(value.Name.Value))
+		}
+		g.printf("},\n},\n")
+	}
+
+	for _, name := range sortedKeys(g.interfaces) {
+		g.printf("%q: &tools.InterfaceResolver{\nResolveType: resolve%sType,\n},\n", name, goName(name))
+	}
+
+	for _, name := range sortedKeys(g.unions) {
+		g.printf("%q: &tools.UnionResolver{\nResolveType: resolve%sType,\n},\n", name, goName(name))
+	}
+
+	for _, name := range g.resolverTypes() {
+		g.generateObjectResolver(name)
+	}
+
+	g.printf("}\n}\n\n")
+	g.generateResolveTypeFuncs()
+}
+
+func (g *generator) generateObjectResolver(name string) {
+	typeName := goName(name)
+	operation, isRoot := g.roots[name]
+
+	g.printf("%q: &tools.ObjectResolver{\nFields: tools.FieldResolveMap{\n", name)
+	for _, field := range g.resolverFields(name) {
+		args := []string{"p.Context"}
+		g.printf("%q: &tools.FieldResolve{\n", field.Name.Value)
+
+		if isRoot && operation == ast.OperationTypeSubscription {
+			g.printf("Resolve: func(p graphql.ResolveParams) (interface{}, error) {\nreturn p.Source, nil\n},\n")
+			g.printf("Subscribe: func(p graphql.ResolveParams) (interface{}, error) {\n")
+		} else {
+			g.printf("Resolve: func(p graphql.ResolveParams) (interface{}, error) {\n")
+		}
+
+		if !isRoot {
+			g.imports["fmt"] = true
+			g.printf("obj, ok := p.Source.(*%s)\n", typeName)
+			g.printf("if !ok {\nreturn nil, fmt.Errorf(\"expected source of type *%s, got %%T\", p.Source)\n}\n", typeName)
+			args = append(args, "obj")
+		}
+
+		if len(field.Arguments) > 0 {
+			g.printf("var args %s\n", argsTypeName(name, field.Name.Value))
+			g.printf("if err := tools.DecodeArgs(p.Args, &args); err != nil {\nreturn nil, err\n}\n")
+			args = append(args, "args")
+		}
+
+		g.printf("return r.%s().%s(%s)\n", typeName, goName(field.Name.Value), strings.Join(args, ", "))
+		g.printf("},\n},\n")
+	}
+	g.printf("},\n},\n")
+}
+
+func (g *generator) generateResolveTypeFuncs() {
+	members := map[string][]string{}
+	for _, name := range sortedKeys(g.objects) {
+		for _, abstract := range g.abstracts[name] {
+			members[abstract] = append(members[abstract], name)
+		}
+	}
+
+	abstracts := []string{}
+	abstracts = append(abstracts, sortedKeys(g.interfaces)...)
+	abstracts = append(abstracts, sortedKeys(g.unions)...)
+
+	for _, name := range abstracts {
+		typeName := goName(name)
+		g.printf("// resolves the object type of a %s\n", typeName)
+		g.printf("func resolve%sType(p graphql.ResolveTypeParams) *graphql.Object {\n", typeName)
+		g.printf("var name string\n")
+		g.printf("switch p.Value.(type) {\n")
+		for _, member := range members[name] {
+			g.printf("case *%s:\nname = %q\n", goName(member), member)
+		}
+		g.printf("}\n")
+		g.printf("if object, ok := p.Info.Schema.Type(name).(*graphql.Object); ok {\nreturn object\n}\n")
+		g.printf("return nil\n}\n\n")
+	}
+}
+
+// gets the go type for a graphql type
+func (g *generator) goType(t ast.Type, nullable bool) string {
+	switch tt := t.(type) {
+	case *ast.NonNull:
+		return g.goType(tt.Type, false)
+	case *ast.List:
+		return "[]" + g.goType(tt.Type, true)
+	case *ast.Named:
+		name := tt.Name.Value
+		goType := g.namedGoType(name)
+
+		// objects and inputs are always pointers, other types only when nullable
+		_, isObject := g.objects[name]
+		_, isInput := g.inputs[name]
+		_, isInterface := g.interfaces[name]
+		_, isUnion := g.unions[name]
+		switch {
+		case isInterface || isUnion:
+			return goType
+		case isObject || isInput:
+			return "*" + goType
+		case nullable && !strings.HasPrefix(goType, "[]") && !strings.HasPrefix(goType, "map[") && goType != "interface{}":
+			return "*" + goType
+		}
+		return goType
+	}
+	return "interface{}"
+}
+
+// gets the go type for a named graphql type
+func (g *generator) namedGoType(name string) string {
+	switch name {
+	case "ID", "String":
+		return "string"
+	case "Int":
+		return "int"
+	case "Float":
+		return "float64"
+	case "Boolean":
+		return "bool"
+	case "DateTime":
+		if goType, ok := g.config.Scalars[name]; ok {
+			return goType
+		}
+		g.imports["time"] = true
+		return "time.Time"
+	}
+
+	if g.scalars[name] {
+		if goType, ok := g.config.Scalars[name]; ok {
+			return goType
+		}
+		return "interface{}"
+	}
+	return goName(name)
+}
+
+// gets the args struct name for a field
+func argsTypeName(typeName, fieldName string) string {
+	return goName(typeName) + goName(fieldName) + "Args"
+}
+
+// returns the sorted keys of a definition map
+func sortedKeys(m interface{}) []string {
+	keys := []string{}
+	switch mm := m.(type) {
+	case map[string]*ast.ObjectDefinition:
+		for key := range mm {
+			keys = append(keys, key)
+		}
+	case map[string]*ast.InterfaceDefinition:
+		for key := range mm {
+			keys = append(keys, key)
+		}
+	case map[string]*ast.UnionDefinition:
+		for key := range mm {
+			keys = append(keys, key)
+		}
+	case map[string]*ast.EnumDefinition:
+		for key := range mm {
+			keys = append(keys, key)
+		}
+	case map[string]*ast.InputObjectDefinition:
+		for key := range mm {
+			keys = append(keys, key)
+		}
+	case map[string]*ast.StringValue:
+		for key := range mm {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
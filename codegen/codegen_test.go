@@ -0,0 +1,84 @@
+package codegen
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	src, err := Generate(Config{
+		Package: "models",
+		TypeDefs: `
+enum Role {
+	ADMIN
+	SUPER_USER
+}
+
+type User {
+	id: ID!
+	name: String
+	role: Role!
+	friends(first: Int): [User!]
+}
+
+type Query {
+	user(id: ID!): User
+}`,
+	})
+	if err != nil {
+		t.Errorf("failed to generate: %v", err)
+		return
+	}
+
+	// type check the generated code importing its dependencies from source so
+	// that the tools package resolves to this module
+	dir, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filepath.Join(dir, "generated.go"), src, 0)
+	if err != nil {
+		t.Errorf("generated invalid go: %v", err)
+		return
+	}
+	conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	if _, err := conf.Check("models", fset, []*ast.File{file}, nil); err != nil {
+		t.Errorf("generated code does not compile: %v\n%s", err, src)
+		return
+	}
+
+	for _, expected := range []string{
+		`RoleSuperUser Role = "SUPER_USER"`,
+		"Name *string `json:\"name\"`",
+		"User(ctx context.Context, args QueryUserArgs) (*User, error)",
+		"Friends(ctx context.Context, obj *User, args UserFriendsArgs) ([]*User, error)",
+		"func NewResolverMap(r ResolverRoot) tools.ResolverMap",
+	} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("expected generated code to contain %q\n%s", expected, src)
+			return
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for name, expected := range map[string]string{
+		"id":         "ID",
+		"userId":     "UserID",
+		"created_at": "CreatedAt",
+		"SUPER_USER": "SuperUser",
+		"URL":        "URL",
+		"htmlBody":   "HTMLBody",
+	} {
+		if actual := goName(name); actual != expected {
+			t.Errorf("expected goName(%q) to be %q, got %q", name, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package codegen
+
+import (
+	"strings"
+	"unicode"
+)
+
+// common initialisms that are kept upper case in go names
+var initialisms = map[string]bool{
+	"API":  true,
+	"HTML": true,
+	"HTTP": true,
+	"ID":   true,
+	"IP":   true,
+	"JSON": true,
+	"SQL":  true,
+	"UID":  true,
+	"URI":  true,
+	"URL":  true,
+	"UUID": true,
+	"XML":  true,
+}
+
+// converts a graphql name to an exported go name
+func goName(name string) string {
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		upper := strings.ToUpper(word)
+		if initialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+
+		runes := []rune(word)
+		b.WriteRune(unicode.ToUpper(runes[0]))
+		rest := string(runes[1:])
+		if word == upper {
+			rest = strings.ToLower(rest)
+		}
+		b.WriteString(rest)
+	}
+	return b.String()
+}
+
+// splits a name into words on underscores and lower to upper case changes
+func splitWords(name string) []string {
+	words := []string{}
+	for _, part := range strings.Split(name, "_") {
+		if part == "" {
+			continue
+		}
+
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			if unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1]) {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
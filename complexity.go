@@ -0,0 +1,418 @@
+package tools
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+const (
+	directiveCost       = "cost"
+	defaultFieldCost    = 1
+	introspectionPrefix = "__"
+	maxInt              = int(^uint(0) >> 1)
+)
+
+// CostDirective sets the complexity of a field and the arguments that multiply it
+// when calculating the complexity of a query
+var CostDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:        directiveCost,
+	Description: "Sets the complexity of a field used to limit the cost of a query",
+	Locations:   []string{graphql.DirectiveLocationFieldDefinition},
+	Args: graphql.FieldConfigArgument{
+		"complexity": &graphql.ArgumentConfig{
+			Type: graphql.Int,
+		},
+		"multipliers": &graphql.ArgumentConfig{
+			Type: graphql.NewList(graphql.String),
+		},
+	},
+})
+
+// FieldCost the cost of a field
+type FieldCost struct {
+	Complexity  int
+	Multipliers []string
+}
+
+// FieldCosts maps a field coordinate like Query.users to its cost
+type FieldCosts map[string]FieldCost
+
+// QueryLimits limits the depth, aliases, and complexity of a query.
+// Limits with a value of 0 are not enforced
+type QueryLimits struct {
+	MaxDepth         int
+	MaxAliases       int
+	MaxComplexity    int
+	DefaultFieldCost int        // cost of a field without a @cost directive, defaults to 1
+	FieldCosts       FieldCosts // field costs, use GetFieldCosts to read them from type definitions
+}
+
+// GetFieldCosts gets the field costs defined by @cost directives in type definitions
+func GetFieldCosts(typeDefs interface{}) (FieldCosts, error) {
+	config := ExecutableSchema{TypeDefs: typeDefs}
+	document, err := config.ConcatenateTypeDefs()
+	if err != nil {
+		return nil, err
+	}
+
+	costs := FieldCosts{}
+	addCosts := func(typeName string, fields []*ast.FieldDefinition) error {
+		for _, field := range fields {
+			for _, directive := range field.Directives {
+				if directive.Name.Value != directiveCost {
+					continue
+				}
+
+				coordinate := typeName + "." + field.Name.Value
+				cost := FieldCost{}
+				for _, arg := range directive.Arguments {
+					switch arg.Name.Value {
+					case "complexity":
+						value, ok := arg.Value.(*ast.IntValue)
+						if !ok {
+							return fmt.Errorf("invalid @cost complexity on %s", coordinate)
+						}
+						if cost.Complexity, err = strconv.Atoi(value.Value); err != nil {
+							return fmt.Errorf("invalid @cost complexity on %s: %v", coordinate, err)
+						}
+					case "multipliers":
+						values, ok := arg.Value.(*ast.ListValue)
+						if !ok {
+							return fmt.Errorf("invalid @cost multipliers on %s", coordinate)
+						}
+						for _, v := range values.Values {
+							name, ok := v.(*ast.StringValue)
+							if !ok {
+								return fmt.Errorf("invalid @cost multipliers on %s", coordinate)
+							}
+							cost.Multipliers = append(cost.Multipliers, name.Value)
+						}
+					}
+				}
+				costs[coordinate] = cost
+			}
+		}
+		return nil
+	}
+
+	for _, def := range document.Definitions {
+		switch node := def.(type) {
+		case *ast.ObjectDefinition:
+			err = addCosts(node.Name.Value, node.Fields)
+		case *ast.TypeExtensionDefinition:
+			err = addCosts(node.Definition.Name.Value, node.Definition.Fields)
+		case *ast.InterfaceDefinition:
+			err = addCosts(node.Name.Value, node.Fields)
+		case *InterfaceExtensionDefinition:
+			err = addCosts(node.Definition.Name.Value, node.Definition.Fields)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return costs, nil
+}
+
+// Validate checks a query against the limits and returns any violations as
+// graphql errors. Queries that fail to parse are not validated so that the
+// syntax error can be reported by the execution
+func (l *QueryLimits) Validate(schema graphql.Schema, query, operationName string, variables map[string]interface{}) []gqlerrors.FormattedError {
+	if l == nil || (l.MaxDepth <= 0 && l.MaxAliases <= 0 && l.MaxComplexity <= 0) {
+		return nil
+	}
+
+	src := source.NewSource(&source.Source{Body: []byte(query), Name: "GraphQL request"})
+	document, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return nil
+	}
+
+	a := &queryAnalyzer{
+		limits:    l,
+		schema:    schema,
+		variables: variables,
+		fragments: map[string]*ast.FragmentDefinition{},
+		analyzed:  map[string]fragmentAnalysis{},
+	}
+
+	var operation *ast.OperationDefinition
+	for _, def := range document.Definitions {
+		switch node := def.(type) {
+		case *ast.FragmentDefinition:
+			a.fragments[node.Name.Value] = node
+		case *ast.OperationDefinition:
+			if operationName == "" || (node.Name != nil && node.Name.Value == operationName) {
+				if operation == nil {
+					operation = node
+				}
+			}
+		}
+	}
+	if operation == nil {
+		return nil
+	}
+
+	rootType := schema.QueryType()
+	switch operation.Operation {
+	case ast.OperationTypeMutation:
+		rootType = schema.MutationType()
+	case ast.OperationTypeSubscription:
+		rootType = schema.SubscriptionType()
+	}
+	if rootType == nil {
+		return nil
+	}
+
+	depth, complexity := a.analyze(rootType, operation.SelectionSet, 1, map[string]bool{})
+
+	errs := []gqlerrors.FormattedError{}
+	addError := func(format string, v ...interface{}) {
+		errs = append(errs, gqlerrors.FormatError(gqlerrors.NewError(
+			fmt.Sprintf(format, v...),
+			[]ast.Node{operation},
+			"",
+			src,
+			nil,
+			nil,
+		)))
+	}
+
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		addError("query has depth %d which exceeds the maximum depth of %d", depth, l.MaxDepth)
+	}
+	if l.MaxAliases > 0 && a.aliases > l.MaxAliases {
+		addError("query has %d aliases which exceeds the maximum of %d", a.aliases, l.MaxAliases)
+	}
+	if l.MaxComplexity > 0 && complexity > l.MaxComplexity {
+		addError("query has complexity %d which exceeds the maximum complexity of %d", complexity, l.MaxComplexity)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+type queryAnalyzer struct {
+	limits    *QueryLimits
+	schema    graphql.Schema
+	variables map[string]interface{}
+	fragments map[string]*ast.FragmentDefinition
+	analyzed  map[string]fragmentAnalysis
+	aliases   int
+}
+
+// the result of analyzing a fragment for a parent type. The depth is relative
+// to the depth the fragment is spread at
+type fragmentAnalysis struct {
+	depth      int
+	complexity int
+	aliases    int
+}
+
+// analyzes a selection set returning its depth and complexity. Fragments are
+// followed once per path to guard against cycles and analyzed once per parent
+// type so that fragments spread many times do not have to be walked again
+func (a *queryAnalyzer) analyze(parentType graphql.Type, selectionSet *ast.SelectionSet, depth int, visited map[string]bool) (int, int) {
+	if selectionSet == nil {
+		return 0, 0
+	}
+
+	maxDepth, complexity := 0, 0
+	collect := func(d, c int) {
+		if d > maxDepth {
+			maxDepth = d
+		}
+		complexity = addCost(complexity, c)
+	}
+
+	for _, selection := range selectionSet.Selections {
+		switch node := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(node.Name.Value, introspectionPrefix) {
+				continue
+			}
+			if node.Alias != nil {
+				a.aliases = addCost(a.aliases, 1)
+			}
+			collect(a.analyzeField(parentType, node, depth, visited))
+
+		case *ast.InlineFragment:
+			fragmentType := parentType
+			if node.TypeCondition != nil {
+				if t := a.schema.Type(node.TypeCondition.Name.Value); t != nil {
+					fragmentType = t
+				}
+			}
+			collect(a.analyze(fragmentType, node.SelectionSet, depth, visited))
+
+		case *ast.FragmentSpread:
+			name := node.Name.Value
+			fragment, ok := a.fragments[name]
+			if !ok || visited[name] {
+				continue
+			}
+			fragmentType := parentType
+			if fragment.TypeCondition != nil {
+				if t := a.schema.Type(fragment.TypeCondition.Name.Value); t != nil {
+					fragmentType = t
+				}
+			}
+			collect(a.analyzeFragment(name, fragmentType, fragment, depth, visited))
+		}
+	}
+
+	return maxDepth, complexity
+}
+
+// analyzes a fragment spread returning its depth and complexity
+func (a *queryAnalyzer) analyzeFragment(name string, fragmentType graphql.Type, fragment *ast.FragmentDefinition, depth int, visited map[string]bool) (int, int) {
+	key := name
+	if fragmentType != nil {
+		key = name + ":" + fragmentType.Name()
+	}
+
+	result, ok := a.analyzed[key]
+	if !ok {
+		aliases := a.aliases
+		visited[name] = true
+		d, c := a.analyze(fragmentType, fragment.SelectionSet, depth, visited)
+		delete(visited, name)
+
+		result = fragmentAnalysis{complexity: c, aliases: a.aliases - aliases}
+		if d > 0 {
+			result.depth = d - depth + 1
+		}
+		a.analyzed[key] = result
+	} else {
+		a.aliases = addCost(a.aliases, result.aliases)
+	}
+
+	if result.depth == 0 {
+		return 0, result.complexity
+	}
+	return depth + result.depth - 1, result.complexity
+}
+
+// analyzes a field returning its depth and complexity
+func (a *queryAnalyzer) analyzeField(parentType graphql.Type, field *ast.Field, depth int, visited map[string]bool) (int, int) {
+	var fieldType graphql.Type
+	if fields := typeFields(parentType); fields != nil {
+		if def, ok := fields[field.Name.Value]; ok {
+			fieldType = getNamedType(def.Type)
+		}
+	}
+
+	cost := FieldCost{Complexity: a.limits.DefaultFieldCost}
+	if a.limits.DefaultFieldCost <= 0 {
+		cost.Complexity = defaultFieldCost
+	}
+	if parentType != nil {
+		if c, ok := a.limits.FieldCosts[parentType.Name()+"."+field.Name.Value]; ok {
+			cost = c
+		}
+	}
+
+	childDepth, childComplexity := a.analyze(fieldType, field.SelectionSet, depth+1, visited)
+	if childDepth < depth {
+		childDepth = depth
+	}
+
+	return childDepth, multiplyCost(addCost(cost.Complexity, childComplexity), a.multiplier(field, cost.Multipliers))
+}
+
+// adds costs without overflowing
+func addCost(a, b int) int {
+	if b > 0 && a > maxInt-b {
+		return maxInt
+	}
+	return a + b
+}
+
+// multiplies costs without overflowing
+func multiplyCost(a, b int) int {
+	if a > 0 && b > 0 && a > maxInt/b {
+		return maxInt
+	}
+	return a * b
+}
+
+// gets the multiplier of a field which is the sum of its multiplier argument values
+func (a *queryAnalyzer) multiplier(field *ast.Field, multipliers []string) int {
+	total := 0
+	found := false
+	for _, name := range multipliers {
+		for _, arg := range field.Arguments {
+			if arg.Name.Value != name {
+				continue
+			}
+			if value, ok := a.argumentSize(arg.Value); ok {
+				total = addCost(total, value)
+				found = true
+			}
+		}
+	}
+	if !found || total < 1 {
+		return 1
+	}
+	return total
+}
+
+// gets the size of an argument value, integers are used as is and
+// lists use their length
+func (a *queryAnalyzer) argumentSize(value ast.Value) (int, bool) {
+	switch v := value.(type) {
+	case *ast.IntValue:
+		i, err := strconv.Atoi(v.Value)
+		return i, err == nil
+	case *ast.ListValue:
+		return len(v.Values), true
+	case *ast.Variable:
+		switch vv := a.variables[v.Name.Value].(type) {
+		case int:
+			return vv, true
+		case int32:
+			return int(vv), true
+		case int64:
+			return int(vv), true
+		case float64:
+			return int(vv), true
+		case []interface{}:
+			return len(vv), true
+		}
+	}
+	return 0, false
+}
+
+// gets the fields of an object or interface type
+func typeFields(t graphql.Type) graphql.FieldDefinitionMap {
+	switch tt := t.(type) {
+	case *graphql.Object:
+		return tt.Fields()
+	case *graphql.Interface:
+		return tt.Fields()
+	}
+	return nil
+}
+
+// unwraps list and non-null types
+func getNamedType(t graphql.Type) graphql.Type {
+	for {
+		switch tt := t.(type) {
+		case *graphql.List:
+			t = tt.OfType
+		case *graphql.NonNull:
+			t = tt.OfType
+		default:
+			return t
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestQueryLimits(t *testing.T) {
+	typeDefs := `
+type User {
+	name: String
+	friends(first: Int): [User] @cost(complexity: 2, multipliers: ["first"])
+}
+
+type Query {
+	user: User
+	users(first: Int, ids: [ID]): [User] @cost(complexity: 3, multipliers: ["first", "ids"])
+}`
+
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: typeDefs,
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	costs, err := GetFieldCosts(typeDefs)
+	if err != nil {
+		t.Errorf("failed to get field costs: %v", err)
+		return
+	}
+	if cost := costs["Query.users"]; cost.Complexity != 3 || strings.Join(cost.Multipliers, ",") != "first,ids" {
+		t.Errorf("unexpected cost for Query.users: %+v", cost)
+		return
+	}
+
+	tests := []struct {
+		name      string
+		limits    QueryLimits
+		query     string
+		variables map[string]interface{}
+		err       string
+	}{
+		{
+			name:   "depth within limit",
+			limits: QueryLimits{MaxDepth: 3},
+			query:  `{ user { friends { name } } }`,
+		},
+		{
+			name:   "depth exceeded through fragment",
+			limits: QueryLimits{MaxDepth: 3},
+			query:  `{ user { ...F } } fragment F on User { friends { friends { name } } }`,
+			err:    "query has depth 4 which exceeds the maximum depth of 3",
+		},
+		{
+			name:   "aliases exceeded",
+			limits: QueryLimits{MaxAliases: 1},
+			query:  `{ a: user { name } b: user { n: name } }`,
+			err:    "query has 3 aliases which exceeds the maximum of 1",
+		},
+		{
+			// (3 + 1) * 10
+			name:   "complexity within limit",
+			limits: QueryLimits{MaxComplexity: 40, FieldCosts: costs},
+			query:  `{ users(first: 10) { name } }`,
+		},
+		{
+			// (3 + (2 + 1) * 5) * (2 + 2)
+			name:      "complexity exceeded with variables",
+			limits:    QueryLimits{MaxComplexity: 50, FieldCosts: costs},
+			query:     `query Q($first: Int) { users(first: $first, ids: ["a", "b"]) { friends(first: 5) { name } } }`,
+			variables: map[string]interface{}{"first": float64(2)},
+			err:       "query has complexity 72 which exceeds the maximum complexity of 50",
+		},
+		{
+			name:   "introspection is not limited",
+			limits: QueryLimits{MaxDepth: 1},
+			query:  `{ __schema { types { fields { type { name } } } } }`,
+		},
+	}
+
+	for _, test := range tests {
+		errs := test.limits.Validate(schema, test.query, "", test.variables)
+		switch {
+		case test.err == "" && len(errs) > 0:
+			t.Errorf("%s: unexpected errors %v", test.name, errs)
+		case test.err != "" && len(errs) != 1:
+			t.Errorf("%s: expected 1 error, got %v", test.name, errs)
+		case test.err != "" && errs[0].Message != test.err:
+			t.Errorf("%s: expected error %q, got %q", test.name, test.err, errs[0].Message)
+		}
+	}
+}
+
+func TestQueryLimitsDuplicatedFragments(t *testing.T) {
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+type User {
+	name: String
+	friend: User
+}
+
+type Query {
+	user: User
+}`,
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	// each fragment spreads the next one twice so walking every spread
+	// would take 2^n steps
+	n := 70
+	query := "{ user { ...F0 } }"
+	for i := 0; i < n; i++ {
+		query += fmt.Sprintf(" fragment F%d on User { a%d: friend { ...F%d } b%d: friend { ...F%d } }", i, i, i+1, i, i+1)
+	}
+	query += fmt.Sprintf(" fragment F%d on User { name }", n)
+
+	limits := QueryLimits{MaxDepth: 100, MaxAliases: 1000, MaxComplexity: 1000}
+	done := make(chan []string)
+	go func() {
+		messages := []string{}
+		for _, e := range limits.Validate(schema, query, "", nil) {
+			messages = append(messages, e.Message)
+		}
+		done <- messages
+	}()
+
+	select {
+	case messages := <-done:
+		expected := []string{
+			fmt.Sprintf("query has %d aliases which exceeds the maximum of 1000", maxInt),
+			fmt.Sprintf("query has complexity %d which exceeds the maximum complexity of 1000", maxInt),
+		}
+		if strings.Join(messages, ",") != strings.Join(expected, ",") {
+			t.Errorf("unexpected errors %v", messages)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("timed out analyzing duplicated fragment spreads")
+	}
+
+	// the depth of a memoized fragment is relative to where it is spread
+	errs := (&QueryLimits{MaxDepth: 3}).Validate(schema, `{ user { ...F } a: user { friend { ...F } } } fragment F on User { friend { name } }`, "", nil)
+	if len(errs) != 1 || errs[0].Message != "query has depth 4 which exceeds the maximum depth of 3" {
+		t.Errorf("unexpected depth errors %v", errs)
+	}
+}
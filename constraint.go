@@ -0,0 +1,217 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/graphql-go/graphql"
+)
+
+const (
+	directiveConstraint = "constraint"
+
+	// Formats supported by the constraint directive
+	FormatEmail = "email"
+	FormatURI   = "uri"
+	FormatUUID  = "uuid"
+)
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// ConstraintDirective validates the value of an argument or input field
+var ConstraintDirective = graphql.NewDirective(graphql.DirectiveConfig{
+	Name:        directiveConstraint,
+	Description: "Validates the value of an argument or input field before the field is resolved",
+	Locations: []string{
+		graphql.DirectiveLocationArgumentDefinition,
+		graphql.DirectiveLocationInputFieldDefinition,
+	},
+	Args: graphql.FieldConfigArgument{
+		"minLength": &graphql.ArgumentConfig{
+			Type: graphql.Int,
+		},
+		"maxLength": &graphql.ArgumentConfig{
+			Type: graphql.Int,
+		},
+		"pattern": &graphql.ArgumentConfig{
+			Type: graphql.String,
+		},
+		"min": &graphql.ArgumentConfig{
+			Type: graphql.Float,
+		},
+		"max": &graphql.ArgumentConfig{
+			Type: graphql.Float,
+		},
+		"format": &graphql.ArgumentConfig{
+			Type:        graphql.String,
+			Description: "One of email, uri or uuid",
+		},
+	},
+})
+
+// ConstraintDirectiveVisitor enforces the @constraint directive. Argument values are
+// validated before the field resolver runs and input field values are validated
+// before the resolver of any field that has the input object in its arguments.
+// Constraints on lists apply to each item
+var ConstraintDirectiveVisitor = &SchemaDirectiveVisitor{
+	VisitArgumentDefinition: func(p VisitArgumentDefinitionParams) error {
+		if p.Field == nil {
+			return nil
+		}
+
+		c, err := newConstraint(p.Args)
+		if err != nil {
+			return err
+		}
+
+		resolve := p.Field.Resolve
+		if resolve == nil {
+			resolve = graphql.DefaultResolveFn
+		}
+
+		argName := p.Node.Name.Value
+		path := p.FieldName + "." + argName
+		p.Field.Resolve = func(rp graphql.ResolveParams) (interface{}, error) {
+			if err := c.validate(rp.Args[argName]); err != nil {
+				return nil, newValidationError(path, err)
+			}
+			return resolve(rp)
+		}
+		return nil
+	},
+	VisitInputFieldDefinition: func(p VisitInputFieldDefinitionParams) error {
+		c, err := newConstraint(p.Args)
+		if err != nil {
+			return err
+		}
+
+		p.AddValidator(func(ctx context.Context, value interface{}) error {
+			return c.validate(value)
+		})
+		return nil
+	},
+}
+
+// constraint the rules of a constraint directive
+type constraint struct {
+	minLength *int
+	maxLength *int
+	pattern   *regexp.Regexp
+	min       *float64
+	max       *float64
+	format    string
+}
+
+// creates a constraint from the directive arguments
+func newConstraint(args map[string]interface{}) (*constraint, error) {
+	c := &constraint{}
+
+	if v, ok := args["minLength"].(int); ok {
+		c.minLength = &v
+	}
+	if v, ok := args["maxLength"].(int); ok {
+		c.maxLength = &v
+	}
+	if v, ok := toFloat(args["min"]); ok {
+		c.min = &v
+	}
+	if v, ok := toFloat(args["max"]); ok {
+		c.max = &v
+	}
+	if v, ok := args["pattern"].(string); ok {
+		pattern, err := regexp.Compile(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid @constraint pattern %q: %v", v, err)
+		}
+		c.pattern = pattern
+	}
+	if v, ok := args["format"].(string); ok {
+		switch v {
+		case FormatEmail, FormatURI, FormatUUID:
+			c.format = v
+		default:
+			return nil, fmt.Errorf("unsupported @constraint format %q", v)
+		}
+	}
+
+	return c, nil
+}
+
+// validates a value against the constraint
+func (c *constraint) validate(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		return nil
+
+	case []interface{}:
+		for _, item := range v {
+			if err := c.validate(item); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case string:
+		length := utf8.RuneCountInString(v)
+		if c.minLength != nil && length < *c.minLength {
+			return fmt.Errorf("must be at least %d characters", *c.minLength)
+		}
+		if c.maxLength != nil && length > *c.maxLength {
+			return fmt.Errorf("must be at most %d characters", *c.maxLength)
+		}
+		if c.pattern != nil && !c.pattern.MatchString(v) {
+			return fmt.Errorf("must match the pattern %q", c.pattern.String())
+		}
+		if c.format != "" && !validFormat(c.format, v) {
+			return fmt.Errorf("must be a valid %s", c.format)
+		}
+		return nil
+	}
+
+	if n, ok := toFloat(value); ok {
+		if c.min != nil && n < *c.min {
+			return fmt.Errorf("must be at least %v", *c.min)
+		}
+		if c.max != nil && n > *c.max {
+			return fmt.Errorf("must be at most %v", *c.max)
+		}
+	}
+
+	return nil
+}
+
+// determines if a string is in a format
+func validFormat(format, value string) bool {
+	switch format {
+	case FormatEmail:
+		addr, err := mail.ParseAddress(value)
+		return err == nil && addr.Address == value
+	case FormatURI:
+		u, err := url.Parse(value)
+		return err == nil && u.Scheme != "" && (u.Host != "" || u.Opaque != "")
+	case FormatUUID:
+		return uuidRegexp.MatchString(value)
+	}
+	return false
+}
+
+// converts a number to a float64
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
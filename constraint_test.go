@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestConstraintDirective(t *testing.T) {
+	typeDefs := `
+input ProfileInput {
+	website: String @constraint(format: "uri")
+}
+
+input UserInput {
+	email: String! @constraint(format: "email")
+	name: String @constraint(minLength: 2, maxLength: 10)
+	profiles: [ProfileInput]
+}
+
+type Query {
+	users(
+		first: Int @constraint(min: 1, max: 100)
+		tags: [String] @constraint(pattern: "^[a-z]+$")
+	): [String]
+}
+
+type Mutation {
+	createUser(input: UserInput!): String
+}
+`
+
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: typeDefs,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"users": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return []string{"user"}, nil
+						},
+					},
+				},
+			},
+			"Mutation": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"createUser": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return "created", nil
+						},
+					},
+				},
+			},
+		},
+		SchemaDirectives: SchemaDirectiveVisitorMap{
+			"constraint": ConstraintDirectiveVisitor,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query string
+		path  string
+	}{
+		{`{ users(first: 10, tags: ["go", "graphql"]) }`, ""},
+		{`{ users(first: 0) }`, "users.first"},
+		{`{ users(tags: ["go", "Go"]) }`, "users.tags"},
+		{`mutation { createUser(input: { email: "a@b.com", name: "bob" }) }`, ""},
+		{`mutation { createUser(input: { email: "not an email" }) }`, "createUser.input.email"},
+		{`mutation { createUser(input: { email: "a@b.com", name: "b" }) }`, "createUser.input.name"},
+		{`mutation { createUser(input: { email: "a@b.com", profiles: [{ website: "https://example.com" }, { website: "example" }] }) }`, "createUser.input.profiles.1.website"},
+	}
+
+	for _, test := range tests {
+		r := graphql.Do(graphql.Params{
+			Schema:        schema,
+			RequestString: test.query,
+		})
+
+		if test.path == "" {
+			if r.HasErrors() {
+				t.Errorf("%s: unexpected errors %v", test.query, r.Errors)
+			}
+			continue
+		}
+
+		if len(r.Errors) != 1 {
+			t.Errorf("%s: expected 1 error, got %v", test.query, r.Errors)
+			continue
+		}
+		if path := r.Errors[0].Extensions["path"]; path != test.path {
+			t.Errorf("%s: expected error at %s, got %v", test.query, test.path, path)
+		}
+	}
+}
+
+func TestConstraintDirectiveInvalidFormat(t *testing.T) {
+	_, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+type Query {
+	user(id: String @constraint(format: "phone")): String
+}`,
+		SchemaDirectives: SchemaDirectiveVisitorMap{
+			"constraint": ConstraintDirectiveVisitor,
+		},
+	})
+	if err == nil {
+		t.Error("expected an unsupported format error")
+	}
+}
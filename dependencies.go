@@ -47,6 +47,22 @@ func (r *registry) IdentifyDependencies() (DependencyMap, error) {
 			}
 		case kinds.SchemaDefinition:
 			identifySchemaDependencies(m, def.(*ast.SchemaDefinition))
+		case kinds.TypeExtensionDefinition:
+			if err := identifyObjectDependencies(m, def.(*ast.TypeExtensionDefinition).Definition); err != nil {
+				return nil, err
+			}
+		case kindInputObjectExtensionDefinition:
+			if err := identifyInputDependencies(m, def.(*InputObjectExtensionDefinition).Definition); err != nil {
+				return nil, err
+			}
+		case kindInterfaceExtensionDefinition:
+			if err := identifyInterfaceDependencies(m, def.(*InterfaceExtensionDefinition).Definition); err != nil {
+				return nil, err
+			}
+		case kindUnionExtensionDefinition:
+			if err := identifyUnionDependencies(m, def.(*UnionExtensionDefinition).Definition); err != nil {
+				return nil, err
+			}
 		}
 	}
 
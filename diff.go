@@ -0,0 +1,598 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// ChangeCriticality describes how a schema change affects existing clients
+type ChangeCriticality string
+
+// change criticality levels
+const (
+	// ChangeBreaking will break existing operations
+	ChangeBreaking ChangeCriticality = "BREAKING"
+	// ChangeDangerous will not break operations but may change client behavior
+	ChangeDangerous ChangeCriticality = "DANGEROUS"
+	// ChangeSafe is backwards compatible
+	ChangeSafe ChangeCriticality = "SAFE"
+)
+
+// ChangeType identifies the kind of schema change
+type ChangeType string
+
+// schema change types
+const (
+	ChangeTypeRemoved                ChangeType = "TYPE_REMOVED"
+	ChangeTypeAdded                  ChangeType = "TYPE_ADDED"
+	ChangeTypeKindChanged            ChangeType = "TYPE_KIND_CHANGED"
+	ChangeRootTypeChanged            ChangeType = "ROOT_TYPE_CHANGED"
+	ChangeFieldRemoved               ChangeType = "FIELD_REMOVED"
+	ChangeFieldAdded                 ChangeType = "FIELD_ADDED"
+	ChangeFieldTypeChanged           ChangeType = "FIELD_TYPE_CHANGED"
+	ChangeFieldDeprecated            ChangeType = "FIELD_DEPRECATED"
+	ChangeArgRemoved                 ChangeType = "ARG_REMOVED"
+	ChangeArgAdded                   ChangeType = "ARG_ADDED"
+	ChangeArgTypeChanged             ChangeType = "ARG_TYPE_CHANGED"
+	ChangeArgDefaultValueChanged     ChangeType = "ARG_DEFAULT_VALUE_CHANGED"
+	ChangeInputFieldRemoved          ChangeType = "INPUT_FIELD_REMOVED"
+	ChangeInputFieldAdded            ChangeType = "INPUT_FIELD_ADDED"
+	ChangeInputFieldTypeChanged      ChangeType = "INPUT_FIELD_TYPE_CHANGED"
+	ChangeInputFieldDefaultChanged   ChangeType = "INPUT_FIELD_DEFAULT_VALUE_CHANGED"
+	ChangeEnumValueRemoved           ChangeType = "ENUM_VALUE_REMOVED"
+	ChangeEnumValueAdded             ChangeType = "ENUM_VALUE_ADDED"
+	ChangeEnumValueDeprecated        ChangeType = "ENUM_VALUE_DEPRECATED"
+	ChangeUnionMemberRemoved         ChangeType = "UNION_MEMBER_REMOVED"
+	ChangeUnionMemberAdded           ChangeType = "UNION_MEMBER_ADDED"
+	ChangeInterfaceRemoved           ChangeType = "INTERFACE_REMOVED"
+	ChangeInterfaceAdded             ChangeType = "INTERFACE_ADDED"
+	ChangeDirectiveRemoved           ChangeType = "DIRECTIVE_REMOVED"
+	ChangeDirectiveAdded             ChangeType = "DIRECTIVE_ADDED"
+	ChangeDirectiveLocationRemoved   ChangeType = "DIRECTIVE_LOCATION_REMOVED"
+	ChangeDirectiveLocationAdded     ChangeType = "DIRECTIVE_LOCATION_ADDED"
+	ChangeDirectiveArgRemoved        ChangeType = "DIRECTIVE_ARG_REMOVED"
+	ChangeDirectiveArgAdded          ChangeType = "DIRECTIVE_ARG_ADDED"
+	ChangeDirectiveArgTypeChanged    ChangeType = "DIRECTIVE_ARG_TYPE_CHANGED"
+	ChangeDirectiveArgDefaultChanged ChangeType = "DIRECTIVE_ARG_DEFAULT_VALUE_CHANGED"
+)
+
+// SchemaChange a single difference between two schemas
+type SchemaChange struct {
+	Type        ChangeType
+	Criticality ChangeCriticality
+	Path        string // schema coordinate of the change, e.g. Type.field.arg or @directive.arg
+	Message     string
+}
+
+// SchemaChanges a list of schema changes
+type SchemaChanges []SchemaChange
+
+// Breaking returns only the breaking changes
+func (c SchemaChanges) Breaking() SchemaChanges {
+	return c.filter(ChangeBreaking)
+}
+
+// Dangerous returns only the dangerous changes
+func (c SchemaChanges) Dangerous() SchemaChanges {
+	return c.filter(ChangeDangerous)
+}
+
+// HasBreaking returns true if any of the changes are breaking
+func (c SchemaChanges) HasBreaking() bool {
+	return len(c.Breaking()) > 0
+}
+
+func (c SchemaChanges) filter(criticality ChangeCriticality) SchemaChanges {
+	changes := SchemaChanges{}
+	for _, change := range c {
+		if change.Criticality == criticality {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// DiffSchemas compares two schemas and returns the changes from oldSchema to newSchema.
+// Each schema can be a graphql.Schema, *graphql.Schema, or any value supported by ExecutableSchema TypeDefs
+func DiffSchemas(oldSchema, newSchema interface{}) (SchemaChanges, error) {
+	o, err := diffSchema(oldSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build old schema: %v", err)
+	}
+
+	n, err := diffSchema(newSchema)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build new schema: %v", err)
+	}
+
+	d := &schemaDiff{changes: SchemaChanges{}}
+	d.diffRootTypes(o, n)
+	d.diffTypes(o.TypeMap(), n.TypeMap())
+	d.diffDirectives(o.Directives(), n.Directives())
+	return d.changes, nil
+}
+
+// gets a schema from a supported diff value
+func diffSchema(value interface{}) (graphql.Schema, error) {
+	switch schema := value.(type) {
+	case graphql.Schema:
+		return schema, nil
+	case *graphql.Schema:
+		if schema == nil {
+			return graphql.Schema{}, fmt.Errorf("nil schema")
+		}
+		return *schema, nil
+	}
+
+	config := ExecutableSchema{TypeDefs: value}
+	document, err := config.ConcatenateTypeDefs()
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+
+	// abstract types require a ResolveType to pass schema validation
+	// since the schema is never executed a no-op resolver is used
+	resolvers := map[string]interface{}{}
+	for _, def := range document.Definitions {
+		switch d := def.(type) {
+		case *ast.InterfaceDefinition:
+			resolvers[d.Name.Value] = &InterfaceResolver{ResolveType: resolveNoType}
+		case *ast.UnionDefinition:
+			resolvers[d.Name.Value] = &UnionResolver{ResolveType: resolveNoType}
+		}
+	}
+
+	config.TypeDefs = document
+	config.Resolvers = resolvers
+	schema, err := config.Make(context.Background())
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+	if schema.QueryType() == nil {
+		return graphql.Schema{}, fmt.Errorf("invalid schema")
+	}
+	return schema, nil
+}
+
+// a ResolveTypeFn that never resolves a type
+func resolveNoType(p graphql.ResolveTypeParams) *graphql.Object {
+	return nil
+}
+
+type schemaDiff struct {
+	changes SchemaChanges
+}
+
+func (d *schemaDiff) add(changeType ChangeType, criticality ChangeCriticality, path, format string, a ...interface{}) {
+	d.changes = append(d.changes, SchemaChange{
+		Type:        changeType,
+		Criticality: criticality,
+		Path:        path,
+		Message:     fmt.Sprintf(format, a...),
+	})
+}
+
+// compares the root operation types
+func (d *schemaDiff) diffRootTypes(o, n graphql.Schema) {
+	roots := []struct {
+		operation string
+		old       *graphql.Object
+		new       *graphql.Object
+	}{
+		{"query", o.QueryType(), n.QueryType()},
+		{"mutation", o.MutationType(), n.MutationType()},
+		{"subscription", o.SubscriptionType(), n.SubscriptionType()},
+	}
+
+	for _, root := range roots {
+		oldName, newName := typeName(root.old), typeName(root.new)
+		switch {
+		case oldName == newName:
+		case oldName == "":
+			d.add(ChangeRootTypeChanged, ChangeSafe, "schema", "%s root type %s was added", root.operation, newName)
+		case newName == "":
+			d.add(ChangeRootTypeChanged, ChangeBreaking, "schema", "%s root type %s was removed", root.operation, oldName)
+		default:
+			d.add(ChangeRootTypeChanged, ChangeBreaking, "schema", "%s root type changed from %s to %s", root.operation, oldName, newName)
+		}
+	}
+}
+
+// compares all named types
+func (d *schemaDiff) diffTypes(o, n graphql.TypeMap) {
+	for _, name := range unionKeys(sortedTypeNames(o), sortedTypeNames(n)) {
+		if isIntrospectionType(name) {
+			continue
+		}
+
+		oldType, inOld := o[name]
+		newType, inNew := n[name]
+		switch {
+		case !inNew:
+			d.add(ChangeTypeRemoved, ChangeBreaking, name, "type %s was removed", name)
+		case !inOld:
+			d.add(ChangeTypeAdded, ChangeSafe, name, "type %s was added", name)
+		case typeKind(oldType) != typeKind(newType):
+			d.add(ChangeTypeKindChanged, ChangeBreaking, name, "type %s changed from %s to %s", name, typeKind(oldType), typeKind(newType))
+		default:
+			switch ot := oldType.(type) {
+			case *graphql.Object:
+				nt := newType.(*graphql.Object)
+				d.diffInterfaces(name, ot.Interfaces(), nt.Interfaces())
+				d.diffFields(name, ot.Fields(), nt.Fields())
+			case *graphql.Interface:
+				d.diffFields(name, ot.Fields(), newType.(*graphql.Interface).Fields())
+			case *graphql.Union:
+				d.diffUnionMembers(name, ot.Types(), newType.(*graphql.Union).Types())
+			case *graphql.Enum:
+				d.diffEnumValues(name, ot.Values(), newType.(*graphql.Enum).Values())
+			case *graphql.InputObject:
+				d.diffInputFields(name, ot.Fields(), newType.(*graphql.InputObject).Fields())
+			}
+		}
+	}
+}
+
+// compares the interfaces implemented by an object
+func (d *schemaDiff) diffInterfaces(typeName string, o, n []*graphql.Interface) {
+	oldNames, newNames := map[string]bool{}, map[string]bool{}
+	for _, iface := range o {
+		oldNames[iface.Name()] = true
+	}
+	for _, iface := range n {
+		newNames[iface.Name()] = true
+	}
+
+	for _, name := range unionKeys(sortedKeys(oldNames), sortedKeys(newNames)) {
+		switch {
+		case !newNames[name]:
+			d.add(ChangeInterfaceRemoved, ChangeBreaking, typeName, "%s no longer implements interface %s", typeName, name)
+		case !oldNames[name]:
+			d.add(ChangeInterfaceAdded, ChangeDangerous, typeName, "%s now implements interface %s", typeName, name)
+		}
+	}
+}
+
+// compares output fields of an object or interface
+func (d *schemaDiff) diffFields(typeName string, o, n graphql.FieldDefinitionMap) {
+	for _, name := range unionKeys(sortedFieldNames(o), sortedFieldNames(n)) {
+		path := typeName + "." + name
+		oldField, inOld := o[name]
+		newField, inNew := n[name]
+
+		switch {
+		case !inNew:
+			d.add(ChangeFieldRemoved, ChangeBreaking, path, "field %s was removed", path)
+			continue
+		case !inOld:
+			d.add(ChangeFieldAdded, ChangeSafe, path, "field %s was added", path)
+			continue
+		}
+
+		if oldType, newType := oldField.Type.String(), newField.Type.String(); oldType != newType {
+			criticality := ChangeBreaking
+			if isSafeOutputTypeChange(oldField.Type, newField.Type) {
+				criticality = ChangeSafe
+			}
+			d.add(ChangeFieldTypeChanged, criticality, path, "field %s changed type from %s to %s", path, oldType, newType)
+		}
+
+		if oldField.DeprecationReason == "" && newField.DeprecationReason != "" {
+			d.add(ChangeFieldDeprecated, ChangeSafe, path, "field %s was deprecated", path)
+		}
+
+		d.diffArgs(path, ChangeArgRemoved, ChangeArgAdded, ChangeArgTypeChanged, ChangeArgDefaultValueChanged, oldField.Args, newField.Args)
+	}
+}
+
+// compares the arguments of a field or directive
+func (d *schemaDiff) diffArgs(parent string, removed, added, typeChanged, defaultChanged ChangeType, o, n []*graphql.Argument) {
+	oldArgs, newArgs := map[string]*graphql.Argument{}, map[string]*graphql.Argument{}
+	for _, arg := range o {
+		oldArgs[arg.Name()] = arg
+	}
+	for _, arg := range n {
+		newArgs[arg.Name()] = arg
+	}
+
+	oldNames, newNames := []string{}, []string{}
+	for name := range oldArgs {
+		oldNames = append(oldNames, name)
+	}
+	for name := range newArgs {
+		newNames = append(newNames, name)
+	}
+	sort.Strings(oldNames)
+	sort.Strings(newNames)
+
+	for _, name := range unionKeys(oldNames, newNames) {
+		path := parent + "." + name
+		oldArg, inOld := oldArgs[name]
+		newArg, inNew := newArgs[name]
+
+		switch {
+		case !inNew:
+			d.add(removed, ChangeBreaking, path, "argument %s was removed", path)
+		case !inOld:
+			if isRequiredInput(newArg.Type, newArg.DefaultValue) {
+				d.add(added, ChangeBreaking, path, "required argument %s was added", path)
+			} else {
+				d.add(added, ChangeDangerous, path, "optional argument %s was added", path)
+			}
+		default:
+			d.diffInputValue(path, "argument", typeChanged, defaultChanged, oldArg.Type, newArg.Type, oldArg.DefaultValue, newArg.DefaultValue)
+		}
+	}
+}
+
+// compares the fields of an input object
+func (d *schemaDiff) diffInputFields(typeName string, o, n graphql.InputObjectFieldMap) {
+	oldNames, newNames := []string{}, []string{}
+	for name := range o {
+		oldNames = append(oldNames, name)
+	}
+	for name := range n {
+		newNames = append(newNames, name)
+	}
+	sort.Strings(oldNames)
+	sort.Strings(newNames)
+
+	for _, name := range unionKeys(oldNames, newNames) {
+		path := typeName + "." + name
+		oldField, inOld := o[name]
+		newField, inNew := n[name]
+
+		switch {
+		case !inNew:
+			d.add(ChangeInputFieldRemoved, ChangeBreaking, path, "input field %s was removed", path)
+		case !inOld:
+			if isRequiredInput(newField.Type, newField.DefaultValue) {
+				d.add(ChangeInputFieldAdded, ChangeBreaking, path, "required input field %s was added", path)
+			} else {
+				d.add(ChangeInputFieldAdded, ChangeDangerous, path, "optional input field %s was added", path)
+			}
+		default:
+			d.diffInputValue(path, "input field", ChangeInputFieldTypeChanged, ChangeInputFieldDefaultChanged, oldField.Type, newField.Type, oldField.DefaultValue, newField.DefaultValue)
+		}
+	}
+}
+
+// compares the type and default value of an argument or input field
+func (d *schemaDiff) diffInputValue(path, description string, typeChanged, defaultChanged ChangeType, oldType, newType graphql.Type, oldDefault, newDefault interface{}) {
+	if o, n := oldType.String(), newType.String(); o != n {
+		criticality := ChangeBreaking
+		if isSafeInputTypeChange(oldType, newType) {
+			criticality = ChangeSafe
+		}
+		d.add(typeChanged, criticality, path, "%s %s changed type from %s to %s", description, path, o, n)
+	}
+
+	if !reflect.DeepEqual(oldDefault, newDefault) {
+		d.add(defaultChanged, ChangeDangerous, path, "%s %s changed default value from %v to %v", description, path, oldDefault, newDefault)
+	}
+}
+
+// compares the values of an enum
+func (d *schemaDiff) diffEnumValues(typeName string, o, n []*graphql.EnumValueDefinition) {
+	oldValues, newValues := map[string]*graphql.EnumValueDefinition{}, map[string]*graphql.EnumValueDefinition{}
+	oldNames, newNames := []string{}, []string{}
+	for _, value := range o {
+		oldValues[value.Name] = value
+		oldNames = append(oldNames, value.Name)
+	}
+	for _, value := range n {
+		newValues[value.Name] = value
+		newNames = append(newNames, value.Name)
+	}
+	sort.Strings(oldNames)
+	sort.Strings(newNames)
+
+	for _, name := range unionKeys(oldNames, newNames) {
+		path := typeName + "." + name
+		oldValue, inOld := oldValues[name]
+		newValue, inNew := newValues[name]
+
+		switch {
+		case !inNew:
+			d.add(ChangeEnumValueRemoved, ChangeBreaking, path, "enum value %s was removed", path)
+		case !inOld:
+			d.add(ChangeEnumValueAdded, ChangeDangerous, path, "enum value %s was added", path)
+		case oldValue.DeprecationReason == "" && newValue.DeprecationReason != "":
+			d.add(ChangeEnumValueDeprecated, ChangeSafe, path, "enum value %s was deprecated", path)
+		}
+	}
+}
+
+// compares the member types of a union
+func (d *schemaDiff) diffUnionMembers(typeName string, o, n []*graphql.Object) {
+	oldNames, newNames := map[string]bool{}, map[string]bool{}
+	for _, member := range o {
+		oldNames[member.Name()] = true
+	}
+	for _, member := range n {
+		newNames[member.Name()] = true
+	}
+
+	for _, name := range unionKeys(sortedKeys(oldNames), sortedKeys(newNames)) {
+		switch {
+		case !newNames[name]:
+			d.add(ChangeUnionMemberRemoved, ChangeBreaking, typeName, "%s was removed from union %s", name, typeName)
+		case !oldNames[name]:
+			d.add(ChangeUnionMemberAdded, ChangeDangerous, typeName, "%s was added to union %s", name, typeName)
+		}
+	}
+}
+
+// compares directive definitions
+func (d *schemaDiff) diffDirectives(o, n []*graphql.Directive) {
+	oldDirectives, newDirectives := map[string]*graphql.Directive{}, map[string]*graphql.Directive{}
+	oldNames, newNames := []string{}, []string{}
+	for _, directive := range o {
+		oldDirectives[directive.Name] = directive
+		oldNames = append(oldNames, directive.Name)
+	}
+	for _, directive := range n {
+		newDirectives[directive.Name] = directive
+		newNames = append(newNames, directive.Name)
+	}
+	sort.Strings(oldNames)
+	sort.Strings(newNames)
+
+	for _, name := range unionKeys(oldNames, newNames) {
+		path := "@" + name
+		oldDirective, inOld := oldDirectives[name]
+		newDirective, inNew := newDirectives[name]
+
+		switch {
+		case !inNew:
+			d.add(ChangeDirectiveRemoved, ChangeBreaking, path, "directive %s was removed", path)
+			continue
+		case !inOld:
+			d.add(ChangeDirectiveAdded, ChangeSafe, path, "directive %s was added", path)
+			continue
+		}
+
+		oldLocations, newLocations := map[string]bool{}, map[string]bool{}
+		for _, loc := range oldDirective.Locations {
+			oldLocations[loc] = true
+		}
+		for _, loc := range newDirective.Locations {
+			newLocations[loc] = true
+		}
+		for _, loc := range unionKeys(sortedKeys(oldLocations), sortedKeys(newLocations)) {
+			switch {
+			case !newLocations[loc]:
+				d.add(ChangeDirectiveLocationRemoved, ChangeBreaking, path, "location %s was removed from directive %s", loc, path)
+			case !oldLocations[loc]:
+				d.add(ChangeDirectiveLocationAdded, ChangeSafe, path, "location %s was added to directive %s", loc, path)
+			}
+		}
+
+		d.diffArgs(path, ChangeDirectiveArgRemoved, ChangeDirectiveArgAdded, ChangeDirectiveArgTypeChanged, ChangeDirectiveArgDefaultChanged, oldDirective.Args, newDirective.Args)
+	}
+}
+
+// determines if changing an output type can not break existing clients.
+// an output type can only become more specific, i.e. nullable to non-null
+func isSafeOutputTypeChange(oldType, newType graphql.Type) bool {
+	switch o := oldType.(type) {
+	case *graphql.NonNull:
+		if n, ok := newType.(*graphql.NonNull); ok {
+			return isSafeOutputTypeChange(o.OfType, n.OfType)
+		}
+		return false
+	case *graphql.List:
+		switch n := newType.(type) {
+		case *graphql.List:
+			return isSafeOutputTypeChange(o.OfType, n.OfType)
+		case *graphql.NonNull:
+			return isSafeOutputTypeChange(oldType, n.OfType)
+		}
+		return false
+	}
+
+	switch n := newType.(type) {
+	case *graphql.NonNull:
+		return isSafeOutputTypeChange(oldType, n.OfType)
+	case *graphql.List:
+		return false
+	}
+	return oldType.Name() == newType.Name()
+}
+
+// determines if changing an input type can not break existing clients.
+// an input type can only become less specific, i.e. non-null to nullable
+func isSafeInputTypeChange(oldType, newType graphql.Type) bool {
+	switch o := oldType.(type) {
+	case *graphql.NonNull:
+		if n, ok := newType.(*graphql.NonNull); ok {
+			return isSafeInputTypeChange(o.OfType, n.OfType)
+		}
+		return isSafeInputTypeChange(o.OfType, newType)
+	case *graphql.List:
+		if n, ok := newType.(*graphql.List); ok {
+			return isSafeInputTypeChange(o.OfType, n.OfType)
+		}
+		return false
+	}
+
+	switch newType.(type) {
+	case *graphql.NonNull, *graphql.List:
+		return false
+	}
+	return oldType.Name() == newType.Name()
+}
+
+// determines if an argument or input field must be provided
+func isRequiredInput(t graphql.Type, defaultValue interface{}) bool {
+	_, isNonNull := t.(*graphql.NonNull)
+	return isNonNull && defaultValue == nil
+}
+
+// gets the name of a type or an empty string if the type is nil
+func typeName(t *graphql.Object) string {
+	if t == nil {
+		return ""
+	}
+	return t.Name()
+}
+
+// gets the definition kind of a named type
+func typeKind(t graphql.Type) string {
+	switch t.(type) {
+	case *graphql.Scalar:
+		return "scalar"
+	case *graphql.Object:
+		return "object"
+	case *graphql.Interface:
+		return "interface"
+	case *graphql.Union:
+		return "union"
+	case *graphql.Enum:
+		return "enum"
+	case *graphql.InputObject:
+		return "input"
+	}
+	return "unknown"
+}
+
+// returns the sorted field names of a field map
+func sortedFieldNames(fieldMap graphql.FieldDefinitionMap) []string {
+	names := []string{}
+	for name := range fieldMap {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// returns the sorted keys of a set
+func sortedKeys(set map[string]bool) []string {
+	keys := []string{}
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// merges two sorted lists of names into a single sorted list of unique names
+func unionKeys(a, b []string) []string {
+	seen := map[string]bool{}
+	keys := []string{}
+	for _, list := range [][]string{a, b} {
+		for _, key := range list {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	oldTypeDefs := `
+enum Role {
+	ADMIN
+	USER
+}
+
+type User {
+	id: ID!
+	name: String
+	email: String
+	role: Role
+}
+
+type Bot {
+	id: ID!
+}
+
+union Actor = User | Bot
+
+input UserFilter {
+	role: Role
+}
+
+type Query {
+	users(filter: UserFilter, limit: Int = 10): [User]
+	actors: [Actor]
+}`
+
+	newTypeDefs := `
+enum Role {
+	ADMIN
+	GUEST
+}
+
+type User {
+	id: ID!
+	name: String!
+	role: Role
+	age: Int
+}
+
+type Bot {
+	id: ID!
+}
+
+union Actor = User
+
+input UserFilter {
+	role: Role
+	active: Boolean!
+}
+
+type Query {
+	users(filter: UserFilter, limit: Int = 20, offset: Int): [User]
+	actors: [Actor]
+}`
+
+	changes, err := DiffSchemas(oldTypeDefs, newTypeDefs)
+	if err != nil {
+		t.Errorf("failed to diff schemas: %v", err)
+		return
+	}
+
+	expected := map[string]ChangeCriticality{
+		"User.email":         ChangeBreaking,
+		"User.name":          ChangeSafe,
+		"User.age":           ChangeSafe,
+		"Role.USER":          ChangeBreaking,
+		"Role.GUEST":         ChangeDangerous,
+		"Actor":              ChangeBreaking,
+		"UserFilter.active":  ChangeBreaking,
+		"Query.users.limit":  ChangeDangerous,
+		"Query.users.offset": ChangeDangerous,
+	}
+
+	if len(changes) != len(expected) {
+		t.Errorf("expected %d changes, got %d: %+v", len(expected), len(changes), changes)
+		return
+	}
+
+	for _, change := range changes {
+		criticality, ok := expected[change.Path]
+		if !ok {
+			t.Errorf("unexpected change %+v", change)
+			return
+		}
+		if change.Criticality != criticality {
+			t.Errorf("expected %s to be %s, got %s: %s", change.Path, criticality, change.Criticality, change.Message)
+			return
+		}
+	}
+
+	if !changes.HasBreaking() || len(changes.Breaking()) != 4 {
+		t.Errorf("expected 4 breaking changes, got %+v", changes.Breaking())
+		return
+	}
+
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: oldTypeDefs,
+		Resolvers: ResolverMap{
+			"Actor": &UnionResolver{
+				ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+					return nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	if schema.QueryType() == nil {
+		t.Error("failed to make schema")
+		return
+	}
+
+	if changes, err = DiffSchemas(schema, &schema); err != nil || len(changes) != 0 {
+		t.Errorf("expected no changes between identical schemas, got %+v, %v", changes, err)
+		return
+	}
+}
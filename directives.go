@@ -2,10 +2,10 @@ package tools
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
 )
 
 const (
@@ -23,7 +23,13 @@ var HideDirective = graphql.NewDirective(graphql.DirectiveConfig{
 // SchemaDirectiveVisitor defines a schema visitor.
 // This attempts to provide similar functionality to Apollo graphql-tools
 // https://www.apollographql.com/docs/graphql-tools/schema-directives/
+//
+// When ApplyToFields is set, directives on objects and interfaces also call
+// VisitFieldDefinition for each of their fields and the fields of the objects
+// implementing the interfaces. Directives on interface fields are applied to
+// the implementing fields. A directive on the field itself takes precedence
 type SchemaDirectiveVisitor struct {
+	ApplyToFields             bool
 	VisitSchema               func(p VisitSchemaParams) error
 	VisitScalar               func(p VisitScalarParams) error
 	VisitObject               func(p VisitObjectParams) error
@@ -47,10 +53,11 @@ type VisitSchemaParams struct {
 
 // VisitScalarParams params
 type VisitScalarParams struct {
-	Context context.Context
-	Config  *graphql.ScalarConfig
-	Node    *ast.ScalarDefinition
-	Args    map[string]interface{}
+	Context    context.Context
+	Config     *graphql.ScalarConfig
+	Node       *ast.ScalarDefinition
+	Extensions []*ast.ScalarDefinition
+	Args       map[string]interface{}
 }
 
 // VisitObjectParams params
@@ -72,36 +79,44 @@ type VisitFieldDefinitionParams struct {
 	ParentKind string
 }
 
-// VisitArgumentDefinitionParams params
+// VisitArgumentDefinitionParams params. Field is the field the argument
+// belongs to and is nil for directive arguments
 type VisitArgumentDefinitionParams struct {
-	Context context.Context
-	Config  *graphql.ArgumentConfig
-	Node    *ast.InputValueDefinition
-	Args    map[string]interface{}
+	Context    context.Context
+	Config     *graphql.ArgumentConfig
+	Node       *ast.InputValueDefinition
+	Args       map[string]interface{}
+	Field      *graphql.Field
+	FieldName  string
+	ParentName string
+	ParentKind string
 }
 
 // VisitInterfaceParams params
 type VisitInterfaceParams struct {
-	Context context.Context
-	Config  *graphql.InterfaceConfig
-	Node    *ast.InterfaceDefinition
-	Args    map[string]interface{}
+	Context    context.Context
+	Config     *graphql.InterfaceConfig
+	Node       *ast.InterfaceDefinition
+	Extensions []*ast.InterfaceDefinition
+	Args       map[string]interface{}
 }
 
 // VisitUnionParams params
 type VisitUnionParams struct {
-	Context context.Context
-	Config  *graphql.UnionConfig
-	Node    *ast.UnionDefinition
-	Args    map[string]interface{}
+	Context    context.Context
+	Config     *graphql.UnionConfig
+	Node       *ast.UnionDefinition
+	Extensions []*ast.UnionDefinition
+	Args       map[string]interface{}
 }
 
 // VisitEnumParams params
 type VisitEnumParams struct {
-	Context context.Context
-	Config  *graphql.EnumConfig
-	Node    *ast.EnumDefinition
-	Args    map[string]interface{}
+	Context    context.Context
+	Config     *graphql.EnumConfig
+	Node       *ast.EnumDefinition
+	Extensions []*ast.EnumDefinition
+	Args       map[string]interface{}
 }
 
 // VisitEnumValueParams params
@@ -114,18 +129,23 @@ type VisitEnumValueParams struct {
 
 // VisitInputObjectParams params
 type VisitInputObjectParams struct {
-	Context context.Context
-	Config  *graphql.InputObjectConfig
-	Node    *ast.InputObjectDefinition
-	Args    map[string]interface{}
+	Context    context.Context
+	Config     *graphql.InputObjectConfig
+	Node       *ast.InputObjectDefinition
+	Extensions []*ast.InputObjectDefinition
+	Args       map[string]interface{}
 }
 
-// VisitInputFieldDefinitionParams params
+// VisitInputFieldDefinitionParams params. AddValidator adds a validator for the
+// input field value that runs before the resolver of any field with the input
+// object in its arguments
 type VisitInputFieldDefinitionParams struct {
-	Context context.Context
-	Config  *graphql.InputObjectFieldConfig
-	Node    *ast.InputValueDefinition
-	Args    map[string]interface{}
+	Context      context.Context
+	Config       *graphql.InputObjectFieldConfig
+	Node         *ast.InputValueDefinition
+	Args         map[string]interface{}
+	ParentName   string
+	AddValidator func(validator InputValidator)
 }
 
 // SchemaDirectiveVisitorMap a map of schema directive visitors
@@ -154,7 +174,7 @@ func (c *registry) buildDirectiveFromAST(definition *ast.DirectiveDefinition) er
 	}
 
 	for _, arg := range definition.Arguments {
-		if argValue, err := c.buildArgFromAST(arg); err == nil {
+		if argValue, err := c.buildArgFromAST(arg, nil, "", ""); err == nil {
 			directiveConfig.Args[arg.Name.Value] = argValue
 		} else {
 			return err
@@ -166,6 +186,7 @@ func (c *registry) buildDirectiveFromAST(definition *ast.DirectiveDefinition) er
 	}
 
 	c.directives[name] = graphql.NewDirective(directiveConfig)
+	delete(c.pendingDirectives, name)
 	return nil
 }
 
@@ -173,15 +194,23 @@ type applyDirectiveParams struct {
 	config     interface{}
 	directives []*ast.Directive
 	node       interface{}
-	extensions []*ast.ObjectDefinition
+	extensions interface{}
+	field      *graphql.Field
 	parentName string
 	parentKind string
+	inherited  bool
 }
 
 // applies directives
 func (c *registry) applyDirectives(p applyDirectiveParams) error {
-	if c.directiveMap == nil {
-		return nil
+	// inherited directives were validated and indexed where they were used
+	if !p.inherited {
+		if err := c.validateDirectives(p.directives, directiveLocation(p.config)); err != nil {
+			return err
+		}
+		if err := c.indexDirectives(p); err != nil {
+			return err
+		}
 	}
 
 	for _, def := range p.directives {
@@ -198,7 +227,7 @@ func (c *registry) applyDirectives(p applyDirectiveParams) error {
 
 		args, err := GetArgumentValues(directive.Args, def.Arguments, map[string]interface{}{})
 		if err != nil {
-			return fmt.Errorf("applyDirective %v error: %s", p.config, err)
+			return newDirectiveError(def, "invalid arguments for directive @%s: %s", name, err)
 		}
 
 		switch p.config.(type) {
@@ -215,23 +244,26 @@ func (c *registry) applyDirectives(p applyDirectiveParams) error {
 			}
 		case *graphql.ScalarConfig:
 			if visitor.VisitScalar != nil {
+				extensions, _ := p.extensions.([]*ast.ScalarDefinition)
 				if err := visitor.VisitScalar(VisitScalarParams{
-					Context: c.ctx,
-					Config:  p.config.(*graphql.ScalarConfig),
-					Args:    args,
-					Node:    p.node.(*ast.ScalarDefinition),
+					Context:    c.ctx,
+					Config:     p.config.(*graphql.ScalarConfig),
+					Args:       args,
+					Node:       p.node.(*ast.ScalarDefinition),
+					Extensions: extensions,
 				}); err != nil {
 					return err
 				}
 			}
 		case *graphql.ObjectConfig:
 			if visitor.VisitObject != nil {
+				extensions, _ := p.extensions.([]*ast.ObjectDefinition)
 				if err := visitor.VisitObject(VisitObjectParams{
 					Context:    c.ctx,
 					Config:     p.config.(*graphql.ObjectConfig),
 					Args:       args,
 					Node:       p.node.(*ast.ObjectDefinition),
-					Extensions: p.extensions,
+					Extensions: extensions,
 				}); err != nil {
 					return err
 				}
@@ -251,44 +283,58 @@ func (c *registry) applyDirectives(p applyDirectiveParams) error {
 			}
 		case *graphql.ArgumentConfig:
 			if visitor.VisitArgumentDefinition != nil {
+				fieldName := ""
+				if p.field != nil {
+					fieldName = p.field.Name
+				}
 				if err := visitor.VisitArgumentDefinition(VisitArgumentDefinitionParams{
-					Context: c.ctx,
-					Config:  p.config.(*graphql.ArgumentConfig),
-					Args:    args,
-					Node:    p.node.(*ast.InputValueDefinition),
+					Context:    c.ctx,
+					Config:     p.config.(*graphql.ArgumentConfig),
+					Args:       args,
+					Node:       p.node.(*ast.InputValueDefinition),
+					Field:      p.field,
+					FieldName:  fieldName,
+					ParentName: p.parentName,
+					ParentKind: p.parentKind,
 				}); err != nil {
 					return err
 				}
 			}
 		case *graphql.InterfaceConfig:
 			if visitor.VisitInterface != nil {
+				extensions, _ := p.extensions.([]*ast.InterfaceDefinition)
 				if err := visitor.VisitInterface(VisitInterfaceParams{
-					Context: c.ctx,
-					Config:  p.config.(*graphql.InterfaceConfig),
-					Args:    args,
-					Node:    p.node.(*ast.InterfaceDefinition),
+					Context:    c.ctx,
+					Config:     p.config.(*graphql.InterfaceConfig),
+					Args:       args,
+					Node:       p.node.(*ast.InterfaceDefinition),
+					Extensions: extensions,
 				}); err != nil {
 					return err
 				}
 			}
 		case *graphql.UnionConfig:
 			if visitor.VisitUnion != nil {
+				extensions, _ := p.extensions.([]*ast.UnionDefinition)
 				if err := visitor.VisitUnion(VisitUnionParams{
-					Context: c.ctx,
-					Config:  p.config.(*graphql.UnionConfig),
-					Args:    args,
-					Node:    p.node.(*ast.UnionDefinition),
+					Context:    c.ctx,
+					Config:     p.config.(*graphql.UnionConfig),
+					Args:       args,
+					Node:       p.node.(*ast.UnionDefinition),
+					Extensions: extensions,
 				}); err != nil {
 					return err
 				}
 			}
 		case *graphql.EnumConfig:
 			if visitor.VisitEnum != nil {
+				extensions, _ := p.extensions.([]*ast.EnumDefinition)
 				if err := visitor.VisitEnum(VisitEnumParams{
-					Context: c.ctx,
-					Config:  p.config.(*graphql.EnumConfig),
-					Args:    args,
-					Node:    p.node.(*ast.EnumDefinition),
+					Context:    c.ctx,
+					Config:     p.config.(*graphql.EnumConfig),
+					Args:       args,
+					Node:       p.node.(*ast.EnumDefinition),
+					Extensions: extensions,
 				}); err != nil {
 					return err
 				}
@@ -306,22 +352,29 @@ func (c *registry) applyDirectives(p applyDirectiveParams) error {
 			}
 		case *graphql.InputObjectConfig:
 			if visitor.VisitInputObject != nil {
+				extensions, _ := p.extensions.([]*ast.InputObjectDefinition)
 				if err := visitor.VisitInputObject(VisitInputObjectParams{
-					Context: c.ctx,
-					Config:  p.config.(*graphql.InputObjectConfig),
-					Args:    args,
-					Node:    p.node.(*ast.InputObjectDefinition),
+					Context:    c.ctx,
+					Config:     p.config.(*graphql.InputObjectConfig),
+					Args:       args,
+					Node:       p.node.(*ast.InputObjectDefinition),
+					Extensions: extensions,
 				}); err != nil {
 					return err
 				}
 			}
 		case *graphql.InputObjectFieldConfig:
 			if visitor.VisitInputFieldDefinition != nil {
+				node := p.node.(*ast.InputValueDefinition)
 				if err := visitor.VisitInputFieldDefinition(VisitInputFieldDefinitionParams{
-					Context: c.ctx,
-					Config:  p.config.(*graphql.InputObjectFieldConfig),
-					Args:    args,
-					Node:    p.node.(*ast.InputValueDefinition),
+					Context:    c.ctx,
+					Config:     p.config.(*graphql.InputObjectFieldConfig),
+					Args:       args,
+					Node:       node,
+					ParentName: p.parentName,
+					AddValidator: func(validator InputValidator) {
+						c.addInputValidator(p.parentName, node.Name.Value, validator)
+					},
 				}); err != nil {
 					return err
 				}
@@ -331,3 +384,94 @@ func (c *registry) applyDirectives(p applyDirectiveParams) error {
 
 	return nil
 }
+
+// gets the directives of an object or interface and its extensions along
+// with the names of the interfaces it implements
+func (c *registry) getTypeDirectives(name string) ([]*ast.Directive, []string) {
+	directives := []*ast.Directive{}
+	interfaces := []string{}
+
+	for _, def := range c.document.Definitions {
+		switch d := def.(type) {
+		case *ast.ObjectDefinition:
+			if d.Name.Value == name {
+				directives = append(directives, d.Directives...)
+				for _, iface := range d.Interfaces {
+					interfaces = append(interfaces, iface.Name.Value)
+				}
+			}
+		case *ast.InterfaceDefinition:
+			if d.Name.Value == name {
+				directives = append(directives, d.Directives...)
+			}
+		}
+	}
+
+	for _, ext := range c.getExtensions(name, kinds.ObjectDefinition) {
+		directives = append(directives, ext.Directives...)
+		for _, iface := range ext.Interfaces {
+			interfaces = append(interfaces, iface.Name.Value)
+		}
+	}
+	for _, ext := range c.getInterfaceExtensions(name) {
+		directives = append(directives, ext.Directives...)
+	}
+
+	return directives, interfaces
+}
+
+// gets the directives of a field on an interface and its extensions
+func (c *registry) getInterfaceFieldDirectives(ifaceName, fieldName string) []*ast.Directive {
+	definitions := c.getInterfaceExtensions(ifaceName)
+	for _, def := range c.document.Definitions {
+		if d, ok := def.(*ast.InterfaceDefinition); ok && d.Name.Value == ifaceName {
+			definitions = append([]*ast.InterfaceDefinition{d}, definitions...)
+		}
+	}
+
+	directives := []*ast.Directive{}
+	for _, def := range definitions {
+		for _, field := range def.Fields {
+			if field.Name.Value == fieldName {
+				directives = append(directives, field.Directives...)
+			}
+		}
+	}
+	return directives
+}
+
+// gets the directives a field inherits from its parent type and the interfaces
+// it implements. Only directives with visitors that apply to fields are
+// inherited and the first directive with a name takes precedence
+func (c *registry) getInheritedFieldDirectives(definition *ast.FieldDefinition, typeName string) []*ast.Directive {
+	if c.directiveMap == nil {
+		return nil
+	}
+
+	typeDirectives, interfaces := c.getTypeDirectives(typeName)
+	candidates := []*ast.Directive{}
+	for _, iface := range interfaces {
+		candidates = append(candidates, c.getInterfaceFieldDirectives(iface, definition.Name.Value)...)
+	}
+	candidates = append(candidates, typeDirectives...)
+	for _, iface := range interfaces {
+		ifaceDirectives, _ := c.getTypeDirectives(iface)
+		candidates = append(candidates, ifaceDirectives...)
+	}
+
+	seen := map[string]bool{}
+	for _, directive := range definition.Directives {
+		seen[directive.Name.Value] = true
+	}
+
+	inherited := []*ast.Directive{}
+	for _, directive := range candidates {
+		name := directive.Name.Value
+		if visitor, ok := c.directiveMap[name]; !ok || visitor == nil || !visitor.ApplyToFields || seen[name] {
+			continue
+		}
+		seen[name] = true
+		inherited = append(inherited, directive)
+	}
+	return inherited
+}
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/graphql-go/graphql"
@@ -95,3 +96,207 @@ type Query {
 		return
 	}
 }
+
+func TestDirectiveValidation(t *testing.T) {
+	tests := []struct {
+		name     string
+		typeDefs string
+		err      string
+	}{
+		{
+			name: "unknown directive",
+			typeDefs: `
+type Query {
+	foo: String @unknown
+}`,
+			err: "unknown directive @unknown (3:14)",
+		},
+		{
+			name: "wrong location",
+			typeDefs: `
+directive @test on FIELD_DEFINITION
+
+type Query @test {
+	foo: String
+}`,
+			err: "directive @test may not be used on OBJECT (4:12)",
+		},
+		{
+			name: "unknown argument",
+			typeDefs: `
+directive @test(message: String) on FIELD_DEFINITION
+
+type Query {
+	foo: String @test(msg: "foo")
+}`,
+			err: `unknown argument "msg" on directive @test (5:14)`,
+		},
+		{
+			name: "duplicate directive",
+			typeDefs: `
+directive @test on OBJECT
+
+type Query @test {
+	foo: String
+}
+
+extend type Query @test {
+	bar: String
+}`,
+			err: "directive @test can only be used once at this location (8:19)",
+		},
+		{
+			// self referencing types build their fields in a thunk
+			name: "unknown directive on self referencing type",
+			typeDefs: `
+type User {
+	name: String @unknown
+	friends: [User]
+}
+
+type Query {
+	u: User
+}`,
+			err: "unknown directive @unknown (3:15)",
+		},
+		{
+			name: "wrong location on self referencing input",
+			typeDefs: `
+directive @test on FIELD_DEFINITION
+
+input Filter {
+	name: String @test
+	and: [Filter]
+}
+
+type Query {
+	users(filter: Filter): String
+}`,
+			err: "directive @test may not be used on INPUT_FIELD_DEFINITION (5:15)",
+		},
+		{
+			name: "missing required argument",
+			typeDefs: `
+directive @test(message: String!) on ARGUMENT_DEFINITION
+
+type Query {
+	foo(bar: String @test): String
+}`,
+			err: `missing required argument "message" on directive @test (5:18)`,
+		},
+	}
+
+	for _, test := range tests {
+		_, err := MakeExecutableSchema(ExecutableSchema{
+			TypeDefs: test.typeDefs,
+		})
+		if err == nil {
+			t.Errorf("%s: expected an error", test.name)
+			continue
+		}
+		if !strings.Contains(err.Error(), test.err) {
+			t.Errorf("%s: expected error %q, got %q", test.name, test.err, err.Error())
+		}
+	}
+
+	// directives declared after their use are valid
+	if _, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+type Query {
+	foo: String @test
+}
+
+directive @test on FIELD_DEFINITION`,
+	}); err != nil {
+		t.Errorf("expected directive declared later to be valid, got %v", err)
+	}
+}
+
+func TestDeprecatedDirective(t *testing.T) {
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+enum Color {
+	RED
+	BLUE @deprecated(reason: "use RED")
+}
+
+type Query {
+	name: String @deprecated
+	title: String
+	color: Color
+}`,
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	fields := schema.QueryType().Fields()
+	if reason := fields["name"].DeprecationReason; reason != graphql.DefaultDeprecationReason {
+		t.Errorf("expected default deprecation reason for name, got %q", reason)
+	}
+	if reason := fields["title"].DeprecationReason; reason != "" {
+		t.Errorf("expected title not to be deprecated, got %q", reason)
+	}
+
+	for _, value := range schema.Type("Color").(*graphql.Enum).Values() {
+		expected := ""
+		if value.Name == "BLUE" {
+			expected = "use RED"
+		}
+		if value.DeprecationReason != expected {
+			t.Errorf("expected deprecation reason %q for %s, got %q", expected, value.Name, value.DeprecationReason)
+		}
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ __type(name: "Query") { fields(includeDeprecated: false) { name } } }`,
+	})
+	if r.HasErrors() {
+		t.Error(r.Errors)
+		return
+	}
+	visible := r.Data.(map[string]interface{})["__type"].(map[string]interface{})["fields"].([]interface{})
+	if len(visible) != 2 {
+		t.Errorf("expected deprecated fields to be excluded from introspection, got %v", visible)
+	}
+}
+
+func TestBuiltinDirectivesOnlyWhenUsed(t *testing.T) {
+	names := func(schema graphql.Schema) map[string]bool {
+		result := map[string]bool{}
+		for _, directive := range schema.Directives() {
+			result[directive.Name] = true
+		}
+		return result
+	}
+
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `type Query { a: String }`,
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+	for _, name := range []string{"hide", "cost", "constraint", "auth"} {
+		if names(schema)[name] {
+			t.Errorf("expected @%s not to be defined by a schema that does not use it", name)
+		}
+	}
+
+	schema, err = MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `type Query { a(b: String @constraint(maxLength: 2)): String @cost(complexity: 2) }`,
+		SchemaDirectives: SchemaDirectiveVisitorMap{
+			"auth": NewAuthDirectiveVisitor(nil),
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+	defined := names(schema)
+	if !defined["cost"] || !defined["constraint"] || !defined["auth"] || defined["hide"] {
+		t.Errorf("expected used directives and directives with visitors to be defined, got %v", defined)
+	}
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+)
+
+// gets the directive location of a type system config
+func directiveLocation(config interface{}) string {
+	switch config.(type) {
+	case *graphql.SchemaConfig:
+		return graphql.DirectiveLocationSchema
+	case *graphql.ScalarConfig:
+		return graphql.DirectiveLocationScalar
+	case *graphql.ObjectConfig:
+		return graphql.DirectiveLocationObject
+	case *graphql.Field:
+		return graphql.DirectiveLocationFieldDefinition
+	case *graphql.ArgumentConfig:
+		return graphql.DirectiveLocationArgumentDefinition
+	case *graphql.InterfaceConfig:
+		return graphql.DirectiveLocationInterface
+	case *graphql.UnionConfig:
+		return graphql.DirectiveLocationUnion
+	case *graphql.EnumConfig:
+		return graphql.DirectiveLocationEnum
+	case *graphql.EnumValueConfig:
+		return graphql.DirectiveLocationEnumValue
+	case *graphql.InputObjectConfig:
+		return graphql.DirectiveLocationInputObject
+	case *graphql.InputObjectFieldConfig:
+		return graphql.DirectiveLocationInputFieldDefinition
+	}
+	return ""
+}
+
+// validates that the directives used at a location are defined, allowed at
+// the location, used once and given only the arguments they define along with
+// the required ones
+func (c *registry) validateDirectives(directives []*ast.Directive, loc string) error {
+	used := map[string]bool{}
+
+	for _, def := range directives {
+		name := def.Name.Value
+
+		directive, err := c.getDirective(name)
+		if err != nil {
+			if c.pendingDirectives[name] {
+				return err
+			}
+			return newDirectiveError(def, "unknown directive @%s", name)
+		}
+
+		if used[name] {
+			return newDirectiveError(def, "directive @%s can only be used once at this location", name)
+		}
+		used[name] = true
+
+		allowed := false
+		for _, l := range directive.Locations {
+			if l == loc {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return newDirectiveError(def, "directive @%s may not be used on %s", name, loc)
+		}
+
+		args := map[string]bool{}
+		for _, arg := range def.Arguments {
+			args[arg.Name.Value] = true
+		}
+		defined := map[string]bool{}
+		for _, arg := range directive.Args {
+			defined[arg.PrivateName] = true
+			if _, nonNull := arg.Type.(*graphql.NonNull); nonNull && arg.DefaultValue == nil && !args[arg.PrivateName] {
+				return newDirectiveError(def, "missing required argument %q on directive @%s", arg.PrivateName, name)
+			}
+		}
+		for _, arg := range def.Arguments {
+			if !defined[arg.Name.Value] {
+				return newDirectiveError(def, "unknown argument %q on directive @%s", arg.Name.Value, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// directiveError an invalid use of a directive in the type definitions
+type directiveError struct {
+	err *gqlerrors.Error
+}
+
+func (e *directiveError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the graphql error with the location of the directive
+func (e *directiveError) Unwrap() error {
+	return e.err
+}
+
+// creates an error for a directive that includes its location in the source
+func newDirectiveError(def *ast.Directive, format string, a ...interface{}) error {
+	message := fmt.Sprintf(format, a...)
+	if loc := def.GetLoc(); loc != nil && loc.Source != nil {
+		l := location.GetLocation(loc.Source, loc.Start)
+		message = fmt.Sprintf("%s (%d:%d)", message, l.Line, l.Column)
+	}
+	return &directiveError{gqlerrors.NewError(message, []ast.Node{def}, "", nil, []int{}, nil)}
+}
@@ -0,0 +1,382 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// ExecutableDirectiveVisitor defines how a directive written in an operation is
+// executed. Resolve is called in place of the field resolver and calls Next to
+// resolve the field. Directives on fields apply to the field, directives on
+// fragments apply to the fields selected by the fragment and directives on the
+// operation apply to its root fields
+type ExecutableDirectiveVisitor struct {
+	Resolve func(p ExecutableDirectiveParams) (interface{}, error)
+}
+
+// ExecutableDirectiveParams params
+type ExecutableDirectiveParams struct {
+	Params   graphql.ResolveParams
+	Args     map[string]interface{}
+	Location string
+	Node     *ast.Directive
+	Next     graphql.FieldResolveFn
+}
+
+// ExecutableDirectiveVisitorMap a map of executable directive visitors
+type ExecutableDirectiveVisitorMap map[string]*ExecutableDirectiveVisitor
+
+// a directive found in an operation and its location
+type operationDirective struct {
+	location string
+	node     *ast.Directive
+	args     map[string]interface{}
+}
+
+// context key of the executable directive cache of a request
+type executableDirectivesKey struct{}
+
+// executableDirectiveCache the directives of the operation being executed by a
+// request keyed by the parent type and path of the fields they apply to
+type executableDirectiveCache struct {
+	mx        sync.Mutex
+	operation ast.Definition
+	used      bool
+	fields    map[string][]operationDirective
+}
+
+// adds an extension that creates an executable directive cache for each request
+func addExecutableDirectiveCache(schema *graphql.Schema) {
+	schema.AddExtensions(&contextExtension{
+		name: "ExecutableDirectives",
+		init: func(ctx context.Context) context.Context {
+			return context.WithValue(ctx, executableDirectivesKey{}, &executableDirectiveCache{})
+		},
+	})
+}
+
+// creates a middleware that executes the directives in an operation
+func executableDirectiveMiddleware(visitors ExecutableDirectiveVisitorMap) FieldMiddleware {
+	return func(m FieldMiddlewareParams) graphql.FieldResolveFn {
+		next := m.Next
+		return func(p graphql.ResolveParams) (interface{}, error) {
+			directives, err := getCachedOperationDirectives(p, visitors)
+			if err != nil {
+				return nil, err
+			}
+			if len(directives) == 0 {
+				return next(p)
+			}
+
+			// the first directive is the outermost
+			resolve := next
+			for i := len(directives) - 1; i >= 0; i-- {
+				directive := directives[i]
+				visitor := visitors[directive.node.Name.Value]
+				inner := resolve
+				resolve = func(rp graphql.ResolveParams) (interface{}, error) {
+					return visitor.Resolve(ExecutableDirectiveParams{
+						Params:   rp,
+						Args:     directive.args,
+						Location: directive.location,
+						Node:     directive.node,
+						Next:     inner,
+					})
+				}
+			}
+
+			return resolve(p)
+		}
+	}
+}
+
+// gets the directives that apply to the field being resolved from the cache of
+// the request. Requests without a cache, such as subscriptions, find the
+// directives for each field
+func getCachedOperationDirectives(p graphql.ResolveParams, visitors ExecutableDirectiveVisitorMap) ([]operationDirective, error) {
+	var cache *executableDirectiveCache
+	if p.Context != nil {
+		cache, _ = p.Context.Value(executableDirectivesKey{}).(*executableDirectiveCache)
+	}
+	if cache == nil {
+		if !usesExecutableDirectives(p.Info, visitors) {
+			return nil, nil
+		}
+		return resolveOperationDirectives(p.Info, visitors)
+	}
+
+	cache.mx.Lock()
+	defer cache.mx.Unlock()
+
+	if cache.operation != p.Info.Operation {
+		cache.operation = p.Info.Operation
+		cache.used = usesExecutableDirectives(p.Info, visitors)
+		cache.fields = map[string][]operationDirective{}
+	}
+	if !cache.used {
+		return nil, nil
+	}
+
+	// list indexes do not change the directives of a field
+	key := ""
+	if p.Info.ParentType != nil {
+		key = p.Info.ParentType.Name()
+	}
+	for _, k := range p.Info.Path.AsArray() {
+		if s, ok := k.(string); ok {
+			key += "." + s
+		}
+	}
+
+	if directives, ok := cache.fields[key]; ok {
+		return directives, nil
+	}
+	directives, err := resolveOperationDirectives(p.Info, visitors)
+	if err != nil {
+		return nil, err
+	}
+	cache.fields[key] = directives
+	return directives, nil
+}
+
+// gets the directives that apply to the field being resolved with their
+// arguments
+func resolveOperationDirectives(info graphql.ResolveInfo, visitors ExecutableDirectiveVisitorMap) ([]operationDirective, error) {
+	directives := getOperationDirectives(info, visitors)
+	for i, directive := range directives {
+		name := directive.node.Name.Value
+		definition := info.Schema.Directive(name)
+		if definition == nil {
+			return nil, fmt.Errorf("no definition found for directive @%s", name)
+		}
+		args, err := GetArgumentValues(definition.Args, directive.node.Arguments, info.VariableValues)
+		if err != nil {
+			return nil, err
+		}
+		directives[i].args = args
+	}
+	return directives, nil
+}
+
+// determines if the operation or its fragments use a directive with a visitor
+func usesExecutableDirectives(info graphql.ResolveInfo, visitors ExecutableDirectiveVisitorMap) bool {
+	hasVisitor := func(directives []*ast.Directive) bool {
+		for _, directive := range directives {
+			if visitor, ok := visitors[directive.Name.Value]; ok && visitor != nil && visitor.Resolve != nil {
+				return true
+			}
+		}
+		return false
+	}
+
+	var usesSelectionSet func(set *ast.SelectionSet) bool
+	usesSelectionSet = func(set *ast.SelectionSet) bool {
+		if set == nil {
+			return false
+		}
+		for _, selection := range set.Selections {
+			switch s := selection.(type) {
+			case *ast.Field:
+				if hasVisitor(s.Directives) || usesSelectionSet(s.SelectionSet) {
+					return true
+				}
+			case *ast.InlineFragment:
+				if hasVisitor(s.Directives) || usesSelectionSet(s.SelectionSet) {
+					return true
+				}
+			case *ast.FragmentSpread:
+				if hasVisitor(s.Directives) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	op, ok := info.Operation.(*ast.OperationDefinition)
+	if !ok {
+		return false
+	}
+	if hasVisitor(op.Directives) || usesSelectionSet(op.SelectionSet) {
+		return true
+	}
+	for _, def := range info.Fragments {
+		if fragment, ok := def.(*ast.FragmentDefinition); ok && usesSelectionSet(fragment.SelectionSet) {
+			return true
+		}
+	}
+	return false
+}
+
+// gets the directives with visitors that apply to the field being resolved,
+// ordered from the operation to the field
+func getOperationDirectives(info graphql.ResolveInfo, visitors ExecutableDirectiveVisitorMap) []operationDirective {
+	directives := []operationDirective{}
+	add := func(location string, nodes []*ast.Directive) {
+		for _, node := range nodes {
+			if visitor, ok := visitors[node.Name.Value]; ok && visitor != nil && visitor.Resolve != nil {
+				directives = append(directives, operationDirective{location: location, node: node})
+			}
+		}
+	}
+
+	// get the response keys of the fields leading to the current field
+	keys := []string{}
+	for _, key := range info.Path.AsArray() {
+		if k, ok := key.(string); ok {
+			keys = append(keys, k)
+		}
+	}
+
+	op, ok := info.Operation.(*ast.OperationDefinition)
+	if ok && len(keys) > 0 {
+		if len(keys) == 1 {
+			add(strings.ToUpper(op.Operation), op.Directives)
+		}
+
+		// follow the path to the selection sets containing the field and
+		// add the directives of the fragments that select it
+		sets := []*ast.SelectionSet{op.SelectionSet}
+		for i, key := range keys {
+			last := i == len(keys)-1
+			next := []*ast.SelectionSet{}
+			for _, set := range sets {
+				visitFieldsWithKey(set, key, info.Fragments, nil, map[string]bool{}, func(field *ast.Field, fragments []operationDirective) {
+					if !last {
+						if field.SelectionSet != nil {
+							next = append(next, field.SelectionSet)
+						}
+						return
+					}
+					for _, fragment := range fragments {
+						add(fragment.location, []*ast.Directive{fragment.node})
+					}
+				})
+			}
+			sets = next
+		}
+	}
+
+	for _, field := range info.FieldASTs {
+		add(graphql.DirectiveLocationField, field.Directives)
+	}
+
+	return dedupeOperationDirectives(directives)
+}
+
+// calls visit with each field in a selection set with the response key along
+// with the directives of the fragments containing it
+func visitFieldsWithKey(
+	set *ast.SelectionSet,
+	key string,
+	fragments map[string]ast.Definition,
+	containers []operationDirective,
+	visited map[string]bool,
+	visit func(field *ast.Field, containers []operationDirective),
+) {
+	if set == nil {
+		return
+	}
+
+	for _, selection := range set.Selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			responseKey := s.Name.Value
+			if s.Alias != nil {
+				responseKey = s.Alias.Value
+			}
+			if responseKey == key {
+				visit(s, containers)
+			}
+
+		case *ast.InlineFragment:
+			visitFieldsWithKey(s.SelectionSet, key, fragments, withContainerDirectives(containers, graphql.DirectiveLocationInlineFragment, s.Directives), visited, visit)
+
+		case *ast.FragmentSpread:
+			name := s.Name.Value
+			if visited[name] {
+				continue
+			}
+			fragment, ok := fragments[name].(*ast.FragmentDefinition)
+			if !ok {
+				continue
+			}
+			visited[name] = true
+			visitFieldsWithKey(fragment.SelectionSet, key, fragments, withContainerDirectives(containers, graphql.DirectiveLocationFragmentSpread, s.Directives), visited, visit)
+			delete(visited, name)
+		}
+	}
+}
+
+// creates a new list of container directives with the directives appended
+func withContainerDirectives(containers []operationDirective, location string, directives []*ast.Directive) []operationDirective {
+	result := make([]operationDirective, 0, len(containers)+len(directives))
+	result = append(result, containers...)
+	for _, directive := range directives {
+		result = append(result, operationDirective{location: location, node: directive})
+	}
+	return result
+}
+
+// removes directives found more than once when fields are selected by more
+// than one fragment
+func dedupeOperationDirectives(directives []operationDirective) []operationDirective {
+	seen := map[*ast.Directive]bool{}
+	result := []operationDirective{}
+	for _, directive := range directives {
+		if seen[directive.node] {
+			continue
+		}
+		seen[directive.node] = true
+		result = append(result, directive)
+	}
+	return result
+}
+
+// contextExtension an extension that only adds values to the context of each
+// request executed with graphql.Do
+type contextExtension struct {
+	name string
+	init func(ctx context.Context) context.Context
+}
+
+func (c *contextExtension) Init(ctx context.Context, p *graphql.Params) context.Context {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return c.init(ctx)
+}
+
+func (c *contextExtension) Name() string {
+	return c.name
+}
+
+func (c *contextExtension) ParseDidStart(ctx context.Context) (context.Context, graphql.ParseFinishFunc) {
+	return ctx, func(err error) {}
+}
+
+func (c *contextExtension) ValidationDidStart(ctx context.Context) (context.Context, graphql.ValidationFinishFunc) {
+	return ctx, func(errs []gqlerrors.FormattedError) {}
+}
+
+func (c *contextExtension) ExecutionDidStart(ctx context.Context) (context.Context, graphql.ExecutionFinishFunc) {
+	return ctx, func(result *graphql.Result) {}
+}
+
+func (c *contextExtension) ResolveFieldDidStart(ctx context.Context, info *graphql.ResolveInfo) (context.Context, graphql.ResolveFieldFinishFunc) {
+	return ctx, func(v interface{}, err error) {}
+}
+
+func (c *contextExtension) HasResult() bool {
+	return false
+}
+
+func (c *contextExtension) GetResult(ctx context.Context) interface{} {
+	return nil
+}
@@ -0,0 +1,217 @@
+package tools
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+func TestExecutableDirectives(t *testing.T) {
+	locations := []string{}
+
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+directive @upper on FIELD | FRAGMENT_SPREAD | INLINE_FRAGMENT
+directive @prefix(value: String!) on FIELD | QUERY
+
+type Foo {
+	name: String
+	description: String
+}
+
+type Query {
+	foo: Foo
+	bar: String
+}`,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"foo": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return map[string]interface{}{"name": "foo", "description": "a foo"}, nil
+						},
+					},
+					"bar": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return "bar", nil
+						},
+					},
+				},
+			},
+		},
+		ExecutableDirectives: ExecutableDirectiveVisitorMap{
+			"upper": &ExecutableDirectiveVisitor{
+				Resolve: func(p ExecutableDirectiveParams) (interface{}, error) {
+					locations = append(locations, p.Location)
+					result, err := p.Next(p.Params)
+					if s, ok := result.(string); ok {
+						return strings.ToUpper(s), err
+					}
+					return result, err
+				},
+			},
+			"prefix": &ExecutableDirectiveVisitor{
+				Resolve: func(p ExecutableDirectiveParams) (interface{}, error) {
+					result, err := p.Next(p.Params)
+					if s, ok := result.(string); ok {
+						return p.Args["value"].(string) + s, err
+					}
+					return result, err
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `
+query ($prefix: String!) @prefix(value: "op:") {
+	bar @upper
+	foo {
+		name @prefix(value: $prefix) @upper
+		...FooFields @upper
+	}
+}
+
+fragment FooFields on Foo {
+	description
+}`,
+		VariableValues: map[string]interface{}{"prefix": "var:"},
+	})
+	if r.HasErrors() {
+		t.Error(r.Errors)
+		return
+	}
+
+	data := r.Data.(map[string]interface{})
+	if data["bar"] != "op:BAR" {
+		t.Errorf("expected operation and field directives on bar, got %v", data["bar"])
+	}
+	foo := data["foo"].(map[string]interface{})
+	if foo["name"] != "var:FOO" {
+		t.Errorf("expected field directives in order on name, got %v", foo["name"])
+	}
+	if foo["description"] != "A FOO" {
+		t.Errorf("expected fragment spread directive on description, got %v", foo["description"])
+	}
+	sort.Strings(locations)
+	if strings.Join(locations, ",") != "FIELD,FIELD,FRAGMENT_SPREAD" {
+		t.Errorf("unexpected directive locations %v", locations)
+	}
+}
+
+func TestExecutableDirectivesCache(t *testing.T) {
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+directive @upper on FIELD
+
+type Item {
+	name: String
+}
+
+type Query {
+	items: [Item]
+}`,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"items": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							items := []interface{}{}
+							for i := 0; i < 100; i++ {
+								items = append(items, map[string]interface{}{"name": "item"})
+							}
+							return items, nil
+						},
+					},
+				},
+			},
+		},
+		ExecutableDirectives: ExecutableDirectiveVisitorMap{
+			"upper": &ExecutableDirectiveVisitor{
+				Resolve: func(p ExecutableDirectiveParams) (interface{}, error) {
+					result, err := p.Next(p.Params)
+					if s, ok := result.(string); ok {
+						return strings.ToUpper(s), err
+					}
+					return result, err
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	check := func(r *graphql.Result, expected string) {
+		if r.HasErrors() {
+			t.Error(r.Errors)
+			return
+		}
+		for _, item := range r.Data.(map[string]interface{})["items"].([]interface{}) {
+			if name := item.(map[string]interface{})["name"]; name != expected {
+				t.Errorf("expected %q, got %v", expected, name)
+				return
+			}
+		}
+	}
+
+	// requests executed with graphql.Do get a cache from the schema extension
+	check(graphql.Do(graphql.Params{Schema: schema, RequestString: `{ items { name @upper } }`}), "ITEM")
+	check(graphql.Do(graphql.Params{Schema: schema, RequestString: `{ items { name } }`}), "item")
+
+	// the directives of a field are found once for every item in a list
+	execute := func(query string) *executableDirectiveCache {
+		cache := &executableDirectiveCache{}
+		check(graphql.Execute(graphql.ExecuteParams{
+			Schema:  schema,
+			AST:     mustParse(t, query),
+			Context: context.WithValue(context.Background(), executableDirectivesKey{}, cache),
+		}), "ITEM")
+		return cache
+	}
+
+	cache := execute(`{ items { ...F } } fragment F on Item { name @upper }`)
+	if !cache.used || len(cache.fields) != 2 {
+		t.Errorf("expected the directives of 2 field paths to be cached, got %v", cache.fields)
+	}
+	if directives := cache.fields["Item.items.name"]; len(directives) != 1 || directives[0].node.Name.Value != "upper" {
+		t.Errorf("unexpected cached directives %v", directives)
+	}
+
+	// operations without directives are not walked for each field
+	cache = &executableDirectiveCache{}
+	r := graphql.Execute(graphql.ExecuteParams{
+		Schema:  schema,
+		AST:     mustParse(t, `{ items { name } }`),
+		Context: context.WithValue(context.Background(), executableDirectivesKey{}, cache),
+	})
+	if r.HasErrors() || cache.used || len(cache.fields) != 0 {
+		t.Errorf("expected no cached directives, got %v %v", cache.fields, r.Errors)
+	}
+
+	// requests without a cache find the directives for each field
+	check(graphql.Execute(graphql.ExecuteParams{
+		Schema: schema,
+		AST:    mustParse(t, `{ items { name @upper } }`),
+	}), "ITEM")
+}
+
+func mustParse(t *testing.T, query string) *ast.Document {
+	document, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{Body: []byte(query)})})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return document
+}
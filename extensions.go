@@ -0,0 +1,277 @@
+package tools
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/lexer"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/printer"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// extension kinds that are not supported by the graphql-go parser
+const (
+	kindScalarExtensionDefinition      = "ScalarExtensionDefinition"
+	kindInterfaceExtensionDefinition   = "InterfaceExtensionDefinition"
+	kindUnionExtensionDefinition       = "UnionExtensionDefinition"
+	kindEnumExtensionDefinition        = "EnumExtensionDefinition"
+	kindInputObjectExtensionDefinition = "InputObjectExtensionDefinition"
+)
+
+// ScalarExtensionDefinition an extend scalar definition
+type ScalarExtensionDefinition struct {
+	Kind       string
+	Loc        *ast.Location
+	Definition *ast.ScalarDefinition
+}
+
+// GetKind gets the kind
+func (def *ScalarExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+// GetLoc gets the location
+func (def *ScalarExtensionDefinition) GetLoc() *ast.Location {
+	return def.Loc
+}
+
+// InterfaceExtensionDefinition an extend interface definition
+type InterfaceExtensionDefinition struct {
+	Kind       string
+	Loc        *ast.Location
+	Definition *ast.InterfaceDefinition
+}
+
+// GetKind gets the kind
+func (def *InterfaceExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+// GetLoc gets the location
+func (def *InterfaceExtensionDefinition) GetLoc() *ast.Location {
+	return def.Loc
+}
+
+// UnionExtensionDefinition an extend union definition
+type UnionExtensionDefinition struct {
+	Kind       string
+	Loc        *ast.Location
+	Definition *ast.UnionDefinition
+}
+
+// GetKind gets the kind
+func (def *UnionExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+// GetLoc gets the location
+func (def *UnionExtensionDefinition) GetLoc() *ast.Location {
+	return def.Loc
+}
+
+// EnumExtensionDefinition an extend enum definition
+type EnumExtensionDefinition struct {
+	Kind       string
+	Loc        *ast.Location
+	Definition *ast.EnumDefinition
+}
+
+// GetKind gets the kind
+func (def *EnumExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+// GetLoc gets the location
+func (def *EnumExtensionDefinition) GetLoc() *ast.Location {
+	return def.Loc
+}
+
+// InputObjectExtensionDefinition an extend input definition
+type InputObjectExtensionDefinition struct {
+	Kind       string
+	Loc        *ast.Location
+	Definition *ast.InputObjectDefinition
+}
+
+// GetKind gets the kind
+func (def *InputObjectExtensionDefinition) GetKind() string {
+	return def.Kind
+}
+
+// GetLoc gets the location
+func (def *InputObjectExtensionDefinition) GetLoc() *ast.Location {
+	return def.Loc
+}
+
+// parses typeDefs with support for extending every kind of type. The graphql-go
+// parser only supports extend type, so the extend keyword of all other extensions
+// is blanked out before parsing and the resulting definitions are wrapped in
+// extension definitions afterwards
+func parseTypeDefs(src *source.Source) (*ast.Document, error) {
+	body := append([]byte{}, src.Body...)
+	extensions := map[int]string{}
+
+	// find the extend keywords at the top level of the document
+	lex := lexer.Lex(src)
+	depth := 0
+	prev := lexer.Token{}
+	for {
+		token, err := lex(0)
+		if err != nil || token.Kind == lexer.EOF {
+			// let the parser report any syntax errors
+			break
+		}
+
+		switch token.Kind {
+		case lexer.BRACE_L, lexer.PAREN_L, lexer.BRACKET_L:
+			depth++
+		case lexer.BRACE_R, lexer.PAREN_R, lexer.BRACKET_R:
+			depth--
+		case lexer.NAME:
+			if depth == 0 && prev.Kind == lexer.NAME && prev.Value == lexer.EXTEND {
+				switch token.Value {
+				case lexer.SCALAR, lexer.INTERFACE, lexer.UNION, lexer.ENUM, lexer.INPUT:
+					for i := prev.Start; i < prev.End; i++ {
+						body[i] = ' '
+					}
+					extensions[token.Start] = token.Value
+				}
+			}
+		}
+		prev = token
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: &source.Source{
+			Body: body,
+			Name: src.Name,
+		},
+	})
+	if err != nil || len(extensions) == 0 {
+		return doc, err
+	}
+
+	// wrap the extended definitions
+	for i, def := range doc.Definitions {
+		if def.GetLoc() == nil {
+			continue
+		}
+		if _, ok := extensions[def.GetLoc().Start]; !ok {
+			continue
+		}
+
+		switch node := def.(type) {
+		case *ast.ScalarDefinition:
+			doc.Definitions[i] = &ScalarExtensionDefinition{
+				Kind:       kindScalarExtensionDefinition,
+				Loc:        node.Loc,
+				Definition: node,
+			}
+		case *ast.InterfaceDefinition:
+			doc.Definitions[i] = &InterfaceExtensionDefinition{
+				Kind:       kindInterfaceExtensionDefinition,
+				Loc:        node.Loc,
+				Definition: node,
+			}
+		case *ast.UnionDefinition:
+			doc.Definitions[i] = &UnionExtensionDefinition{
+				Kind:       kindUnionExtensionDefinition,
+				Loc:        node.Loc,
+				Definition: node,
+			}
+		case *ast.EnumDefinition:
+			doc.Definitions[i] = &EnumExtensionDefinition{
+				Kind:       kindEnumExtensionDefinition,
+				Loc:        node.Loc,
+				Definition: node,
+			}
+		case *ast.InputObjectDefinition:
+			doc.Definitions[i] = &InputObjectExtensionDefinition{
+				Kind:       kindInputObjectExtensionDefinition,
+				Loc:        node.Loc,
+				Definition: node,
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// prints a type definition including the extensions the graphql-go printer does not support
+func printTypeDef(def ast.Node) interface{} {
+	switch node := def.(type) {
+	case *ScalarExtensionDefinition:
+		return printExtension(node.Definition)
+	case *InterfaceExtensionDefinition:
+		return printExtension(node.Definition)
+	case *UnionExtensionDefinition:
+		return printExtension(node.Definition)
+	case *EnumExtensionDefinition:
+		return printExtension(node.Definition)
+	case *InputObjectExtensionDefinition:
+		return printExtension(node.Definition)
+	}
+	return printer.Print(def)
+}
+
+// prints an extended definition
+func printExtension(def ast.Node) interface{} {
+	if printed, ok := printer.Print(def).(string); ok {
+		return "extend " + printed
+	}
+	return nil
+}
+
+// gets the extensions for a scalar
+func (c *registry) getScalarExtensions(name string) []*ast.ScalarDefinition {
+	extensions := []*ast.ScalarDefinition{}
+	for _, def := range c.document.Definitions {
+		if ext, ok := def.(*ScalarExtensionDefinition); ok && ext.Definition.Name.Value == name {
+			extensions = append(extensions, ext.Definition)
+		}
+	}
+	return extensions
+}
+
+// gets the extensions for an interface
+func (c *registry) getInterfaceExtensions(name string) []*ast.InterfaceDefinition {
+	extensions := []*ast.InterfaceDefinition{}
+	for _, def := range c.document.Definitions {
+		if ext, ok := def.(*InterfaceExtensionDefinition); ok && ext.Definition.Name.Value == name {
+			extensions = append(extensions, ext.Definition)
+		}
+	}
+	return extensions
+}
+
+// gets the extensions for a union
+func (c *registry) getUnionExtensions(name string) []*ast.UnionDefinition {
+	extensions := []*ast.UnionDefinition{}
+	for _, def := range c.document.Definitions {
+		if ext, ok := def.(*UnionExtensionDefinition); ok && ext.Definition.Name.Value == name {
+			extensions = append(extensions, ext.Definition)
+		}
+	}
+	return extensions
+}
+
+// gets the extensions for an enum
+func (c *registry) getEnumExtensions(name string) []*ast.EnumDefinition {
+	extensions := []*ast.EnumDefinition{}
+	for _, def := range c.document.Definitions {
+		if ext, ok := def.(*EnumExtensionDefinition); ok && ext.Definition.Name.Value == name {
+			extensions = append(extensions, ext.Definition)
+		}
+	}
+	return extensions
+}
+
+// gets the extensions for an input object
+func (c *registry) getInputObjectExtensions(name string) []*ast.InputObjectDefinition {
+	extensions := []*ast.InputObjectDefinition{}
+	for _, def := range c.document.Definitions {
+		if ext, ok := def.(*InputObjectExtensionDefinition); ok && ext.Definition.Name.Value == name {
+			extensions = append(extensions, ext.Definition)
+		}
+	}
+	return extensions
+}
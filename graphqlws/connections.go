@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	wsserver "github.com/bhoriuchi/graphql-go-tools/server/graphqlws"
 	"github.com/google/uuid"
 	"github.com/gorilla/websocket"
 )
@@ -26,8 +27,8 @@ const (
 	gqlComplete            = "complete"
 	gqlStop                = "stop"
 
-	// Maximum size of incoming messages
-	readLimit = 4096
+	// Default maximum size of incoming messages
+	defaultReadLimit = 4096
 
 	// Timeout for outgoing messages
 	writeTimeout = 10 * time.Second
@@ -101,6 +102,10 @@ type ConnectionConfig struct {
 	Logger        Logger
 	Authenticate  AuthenticateFunc
 	EventHandlers ConnectionEventHandlers
+
+	// ReadLimit is the maximum size in bytes of incoming messages,
+	// defaults to 4096
+	ReadLimit int64
 }
 
 // Connection is an interface to represent GraphQL WebSocket connections.
@@ -153,6 +158,9 @@ func NewConnection(ws *websocket.Conn, config ConnectionConfig) Connection {
 	conn.ws = ws
 	conn.context = context.Background()
 	conn.config = config
+	if conn.config.ReadLimit <= 0 {
+		conn.config.ReadLimit = defaultReadLimit
+	}
 	conn.logger = config.Logger
 	conn.closed = false
 	conn.closeMutex = &sync.Mutex{}
@@ -259,7 +267,7 @@ func (conn *connection) writeLoop() {
 func (conn *connection) readLoop() {
 	// Close the WebSocket connection when leaving the read loop
 	defer conn.ws.Close()
-	conn.ws.SetReadLimit(readLimit)
+	conn.ws.SetReadLimit(conn.config.ReadLimit)
 
 	for {
 		// Read the next message received from the client
@@ -267,7 +275,17 @@ func (conn *connection) readLoop() {
 		msg := OperationMessage{
 			Payload: &rawPayload,
 		}
-		err := conn.ws.ReadJSON(&msg)
+		data, err := wsserver.ReadMessage(conn.ws, conn.config.ReadLimit)
+		if err == wsserver.ErrMessageTooBig {
+			conn.ws.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseMessageTooBig, "Message too big"),
+				time.Now().Add(writeTimeout),
+			)
+		}
+		if err == nil {
+			err = json.Unmarshal(data, &msg)
+		}
 
 		// If this causes an error, close the connection and read loop immediately;
 		// see https://github.com/gorilla/websocket/blob/master/conn.go#L924 for
@@ -294,7 +312,7 @@ func (conn *connection) readLoop() {
 						msg.Payload = fmt.Sprintf("Failed to authenticate user: %v", err)
 						conn.outgoing <- msg
 					} else {
-						conn.context = ctx
+						conn.context = authenticatedContext(ctx)
 					}
 				}
 			}
@@ -313,7 +331,7 @@ func (conn *connection) readLoop() {
 						msg.Payload = fmt.Sprintf("Failed to authenticate user: %v", err)
 						conn.outgoing <- msg
 					} else {
-						conn.context = ctx
+						conn.context = authenticatedContext(ctx)
 						conn.outgoing <- operationMessageForType(gqlConnectionAck)
 					}
 				} else {
@@ -356,3 +374,12 @@ func (conn *connection) readLoop() {
 		}
 	}
 }
+
+// authenticatedContext falls back to a background context when an
+// AuthenticateFunc returns no context
+func authenticatedContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
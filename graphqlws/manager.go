@@ -1,6 +1,7 @@
 package graphqlws
 
 import (
+	"context"
 	"sync"
 
 	"github.com/graphql-go/graphql"
@@ -12,10 +13,11 @@ type ChanMgr struct {
 }
 
 type ResultChan struct {
-	ch chan *graphql.Result
+	ch         chan *graphql.Result
+	cancelFunc context.CancelFunc
 }
 
-func (c *ChanMgr) Add(cid, oid string, ch chan *graphql.Result) {
+func (c *ChanMgr) Add(cid, oid string, ch chan *graphql.Result, cancelFunc context.CancelFunc) {
 	c.mx.Lock()
 	defer c.mx.Unlock()
 
@@ -26,7 +28,8 @@ func (c *ChanMgr) Add(cid, oid string, ch chan *graphql.Result) {
 	}
 
 	conn[oid] = &ResultChan{
-		ch: ch,
+		ch:         ch,
+		cancelFunc: cancelFunc,
 	}
 }
 
@@ -39,7 +42,10 @@ func (c *ChanMgr) DelConn(cid string) bool {
 		return false
 	}
 
-	for oid := range conn {
+	for oid, rc := range conn {
+		if rc.cancelFunc != nil {
+			rc.cancelFunc()
+		}
 		delete(conn, oid)
 	}
 
@@ -56,10 +62,14 @@ func (c *ChanMgr) Del(cid, oid string) bool {
 		return false
 	}
 
-	if _, ok := conn[oid]; !ok {
+	rc, ok := conn[oid]
+	if !ok {
 		return false
 	}
 
+	if rc.cancelFunc != nil {
+		rc.cancelFunc()
+	}
 	delete(conn, oid)
 
 	if len(c.conns[cid]) == 0 {
@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 
+	tools "github.com/bhoriuchi/graphql-go-tools"
+	wsserver "github.com/bhoriuchi/graphql-go-tools/server/graphqlws"
 	"github.com/gorilla/websocket"
 	"github.com/graphql-go/graphql"
 )
@@ -17,13 +19,33 @@ type HandlerConfig struct {
 	Authenticate AuthenticateFunc
 	Schema       graphql.Schema
 	RootValue    map[string]interface{}
+	Limits       *tools.QueryLimits
+
+	ReadLimit int64 // maximum size in bytes of incoming messages, defaults to 4096
+
+	// CheckOrigin validates the Origin header of upgrade requests. When not set
+	// the origin must be in AllowedOrigins, all origins are allowed when both are empty
+	CheckOrigin    func(r *http.Request) bool
+	AllowedOrigins []string // allowed origins such as https://example.com, * allows all
+
+	EnableCompression bool // negotiates permessage-deflate compression with clients
+	ReadBufferSize    int  // size of the read buffer in bytes, defaults to 4096
+	WriteBufferSize   int  // size of the write buffer in bytes, defaults to 4096
 }
 
 // NewHandler creates a new handler
 func NewHandler(config HandlerConfig) http.Handler {
 	var upgrader = websocket.Upgrader{
-		CheckOrigin:  func(r *http.Request) bool { return true },
-		Subprotocols: []string{"graphql-ws"},
+		CheckOrigin:       func(r *http.Request) bool { return true },
+		Subprotocols:      []string{"graphql-ws"},
+		EnableCompression: config.EnableCompression,
+		ReadBufferSize:    config.ReadBufferSize,
+		WriteBufferSize:   config.WriteBufferSize,
+	}
+	if config.CheckOrigin != nil {
+		upgrader.CheckOrigin = config.CheckOrigin
+	} else if len(config.AllowedOrigins) > 0 {
+		upgrader.CheckOrigin = wsserver.AllowOrigins(config.AllowedOrigins)
 	}
 
 	mgr := &ChanMgr{
@@ -56,6 +78,7 @@ func NewHandler(config HandlerConfig) http.Handler {
 			NewConnection(ws, ConnectionConfig{
 				Authenticate: config.Authenticate,
 				Logger:       config.Logger,
+				ReadLimit:    config.ReadLimit,
 				EventHandlers: ConnectionEventHandlers{
 					Close: func(conn Connection) {
 						config.Logger.Debugf("closing websocket: %s", conn.ID)
@@ -68,7 +91,16 @@ func NewHandler(config HandlerConfig) http.Handler {
 					) []error {
 						config.Logger.Debugf("start operations %s on connection %s", opID, conn.ID())
 
-						ctx := context.WithValue(context.Background(), ConnKey, conn)
+						if errs := config.Limits.Validate(config.Schema, data.Query, data.OperationName, data.Variables); errs != nil {
+							opErrs := make([]error, len(errs))
+							for i, err := range errs {
+								opErrs[i] = err
+							}
+							return opErrs
+						}
+
+						// use the values of the authenticated connection context in the operation
+						ctx, cancelFunc := context.WithCancel(context.WithValue(wsserver.DetachedContext(conn.Context()), ConnKey, conn))
 						resultChannel := graphql.Subscribe(graphql.Params{
 							Schema:         config.Schema,
 							RequestString:  data.Query,
@@ -78,7 +110,7 @@ func NewHandler(config HandlerConfig) http.Handler {
 							RootObject:     config.RootValue,
 						})
 
-						mgr.Add(conn.ID(), opID, resultChannel)
+						mgr.Add(conn.ID(), opID, resultChannel, cancelFunc)
 
 						go func() {
 							for {
@@ -88,6 +120,7 @@ func NewHandler(config HandlerConfig) http.Handler {
 									return
 								case res, more := <-resultChannel:
 									if !more {
+										mgr.Del(conn.ID(), opID)
 										return
 									}
 
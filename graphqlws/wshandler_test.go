@@ -0,0 +1,164 @@
+package graphqlws
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql"
+)
+
+func TestHandlerAuthenticateWithoutContext(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{Type: graphql.String},
+			},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						ch := make(chan interface{}, 1)
+						ch <- "world"
+						return ch, nil
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewHandler(HandlerConfig{
+		Schema: schema,
+		Authenticate: func(data map[string]interface{}, conn Connection) (context.Context, error) {
+			return nil, nil
+		},
+		AllowedOrigins: []string{"https://example.com"},
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-ws"}}
+
+	// disallowed origins cannot connect
+	if _, res, err := dialer.Dial(url, http.Header{"Origin": {"https://evil.com"}}); err == nil || res.StatusCode != http.StatusForbidden {
+		t.Error("expected the origin to be forbidden")
+	}
+
+	ws, _, err := dialer.Dial(url, http.Header{"Origin": {"https://example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	// operations use a background context when authenticate returns none
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	messages := []OperationMessage{
+		{Type: gqlConnectionInit, Payload: map[string]interface{}{}},
+		{ID: "1", Type: gqlStart, Payload: map[string]interface{}{"query": "subscription { hello }"}},
+	}
+	expected := []string{gqlConnectionAck, gqlData}
+	for i, msg := range messages {
+		if err := ws.WriteJSON(msg); err != nil {
+			t.Fatal(err)
+		}
+		reply := map[string]interface{}{}
+		if err := ws.ReadJSON(&reply); err != nil {
+			t.Fatal(err)
+		}
+		if reply["type"] != expected[i] {
+			t.Errorf("expected %s, got %v", expected[i], reply)
+		}
+	}
+}
+
+type testContextKey string
+
+func TestHandlerDetachesAuthenticateContext(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{Type: graphql.String},
+			},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"user": &graphql.Field{
+					Type: graphql.String,
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						ch := make(chan interface{}, 1)
+						ch <- p.Context.Value(testContextKey("user"))
+						return ch, nil
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(NewHandler(HandlerConfig{
+		Schema: schema,
+		Authenticate: func(data map[string]interface{}, conn Connection) (context.Context, error) {
+			// the context is done before any operation starts
+			ctx, cancel := context.WithCancel(context.WithValue(context.Background(), testContextKey("user"), "alice"))
+			cancel()
+			return ctx, nil
+		},
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-ws"}}
+	ws, _, err := dialer.Dial(url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	messages := []OperationMessage{
+		{Type: gqlConnectionInit, Payload: map[string]interface{}{}},
+		{ID: "1", Type: gqlStart, Payload: map[string]interface{}{"query": "subscription { user }"}},
+	}
+	expected := []string{gqlConnectionAck, gqlData}
+	var reply map[string]interface{}
+	for i, msg := range messages {
+		if err := ws.WriteJSON(msg); err != nil {
+			t.Fatal(err)
+		}
+		reply = map[string]interface{}{}
+		if err := ws.ReadJSON(&reply); err != nil {
+			t.Fatal(err)
+		}
+		if reply["type"] != expected[i] {
+			t.Fatalf("expected %s, got %v", expected[i], reply)
+		}
+	}
+
+	// operations keep the authenticate values but not its cancellation
+	payload, _ := reply["payload"].(map[string]interface{})
+	data, _ := payload["data"].(map[string]interface{})
+	if data["user"] != "alice" {
+		t.Errorf("expected the user from the authenticate context, got %v", reply)
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhoriuchi/graphql-go-tools/internal/batch"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// BatchConfig configuration for executing batched requests
+type BatchConfig struct {
+	MaxBatchSize   int // maximum number of operations in a batch, 0 is unlimited
+	MaxConcurrency int // number of operations executed concurrently, defaults to 1
+}
+
+// NewBatchRequestOptions Parses a http.Request with a JSON array body into a batch of
+// GraphQL request options. If the request is not a batch the body is restored and false is returned
+func NewBatchRequestOptions(r *http.Request) ([]*RequestOptions, bool) {
+	items, ok := batch.ReadRequest(r)
+	if !ok {
+		return nil, false
+	}
+
+	opts := make([]*RequestOptions, len(items))
+	for i, item := range items {
+		opts[i] = unmarshalRequestOptions(item)
+	}
+	return opts, true
+}
+
+// executes a batch of queries and writes an array of results
+func (h *Handler) batchHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, opts []*RequestOptions) {
+	if err := batch.Validate(len(opts), h.batchConfig.MaxBatchSize); err != nil {
+		h.writeJSON(w, http.StatusBadRequest, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+
+	params := make([]graphql.Params, len(opts))
+	results := make([]*graphql.Result, len(opts))
+	batch.Run(len(opts), h.batchConfig.MaxConcurrency, func(i int) {
+		params[i], results[i] = h.execute(ctx, r, opts[i])
+	})
+
+	h.writeJSON(w, http.StatusOK, results)
+
+	if h.resultCallbackFn != nil {
+		for i, result := range results {
+			buff, _ := json.Marshal(result)
+			h.resultCallbackFn(ctx, &params[i], result, buff)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	h := newTestHandler(t, &Config{
+		BatchConfig: &BatchConfig{MaxBatchSize: 2, MaxConcurrency: 2},
+	})
+
+	// results are returned in the order of the operations
+	w := postJSON(t, h, []map[string]interface{}{
+		{"query": `{ hello }`},
+		{"query": `query ($text: String) { echo(text: $text) }`, "variables": map[string]interface{}{"text": "hi"}},
+	})
+	assertJSON(t, w.Body.Bytes(), `[{"data":{"hello":"world"}},{"data":{"echo":"hi"}}]`)
+
+	// batches larger than the maximum are rejected
+	w = postJSON(t, h, []map[string]interface{}{
+		{"query": `{ hello }`}, {"query": `{ hello }`}, {"query": `{ hello }`},
+	})
+	if w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "exceeds the maximum of 2") {
+		t.Errorf("expected the batch size to be rejected, got %d %s", w.Code, w.Body.String())
+	}
+
+	// single operations are not batches
+	w = postJSON(t, h, map[string]interface{}{"query": `{ hello }`})
+	assertJSON(t, w.Body.Bytes(), `{"data":{"hello":"world"}}`)
+}
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"strings"
 
+	tools "github.com/bhoriuchi/graphql-go-tools"
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/gqlerrors"
 )
@@ -31,6 +32,8 @@ type Handler struct {
 	rootObjectFn     RootObjectFn
 	resultCallbackFn ResultCallbackFn
 	formatErrorFn    func(err error) gqlerrors.FormattedError
+	limits           *tools.QueryLimits
+	batchConfig      *BatchConfig
 }
 
 // RequestOptions options
@@ -65,6 +68,19 @@ func getFromForm(values url.Values) *RequestOptions {
 	return nil
 }
 
+// parses a JSON body into request options
+func unmarshalRequestOptions(body []byte) *RequestOptions {
+	var opts RequestOptions
+	if err := json.Unmarshal(body, &opts); err != nil {
+		// Probably `variables` was sent as a string instead of an object.
+		// So, we try to be polite and try to parse that as a JSON string
+		var optsCompatible requestOptionsCompatibility
+		json.Unmarshal(body, &optsCompatible)
+		json.Unmarshal([]byte(optsCompatible.Variables), &opts.Variables)
+	}
+	return &opts
+}
+
 // NewRequestOptions Parses a http.Request into GraphQL request options struct
 func NewRequestOptions(r *http.Request) *RequestOptions {
 	if reqOpt := getFromForm(r.URL.Query()); reqOpt != nil {
@@ -107,30 +123,58 @@ func NewRequestOptions(r *http.Request) *RequestOptions {
 	case ContentTypeJSON:
 		fallthrough
 	default:
-		var opts RequestOptions
 		body, err := ioutil.ReadAll(r.Body)
 		if err != nil {
-			return &opts
-		}
-		err = json.Unmarshal(body, &opts)
-		if err != nil {
-			// Probably `variables` was sent as a string instead of an object.
-			// So, we try to be polite and try to parse that as a JSON string
-			var optsCompatible requestOptionsCompatibility
-			json.Unmarshal(body, &optsCompatible)
-			json.Unmarshal([]byte(optsCompatible.Variables), &opts.Variables)
+			return &RequestOptions{}
 		}
-		return &opts
+		return unmarshalRequestOptions(body)
 	}
 }
 
 // ContextHandler provides an entrypoint into executing graphQL queries with a
 // user-provided context.
 func (h *Handler) ContextHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	// execute a batch of queries
+	if h.batchConfig != nil {
+		if batch, ok := NewBatchRequestOptions(r); ok {
+			h.batchHandler(ctx, w, r, batch)
+			return
+		}
+	}
+
 	// get query
 	opts := NewRequestOptions(r)
 
 	// execute graphql query
+	params, result := h.execute(ctx, r, opts)
+
+	if h.graphiqlConfig != nil {
+		acceptHeader := r.Header.Get("Accept")
+		_, raw := r.URL.Query()["raw"]
+		if !raw && !strings.Contains(acceptHeader, "application/json") && strings.Contains(acceptHeader, "text/html") {
+			renderGraphiQL(h.graphiqlConfig, w, r, params)
+			return
+		}
+	}
+
+	if h.playgroundConfig != nil && h.graphiqlConfig == nil {
+		acceptHeader := r.Header.Get("Accept")
+		_, raw := r.URL.Query()["raw"]
+		if !raw && !strings.Contains(acceptHeader, "application/json") && strings.Contains(acceptHeader, "text/html") {
+			renderPlayground(h.playgroundConfig, w, r)
+			return
+		}
+	}
+
+	buff := h.writeJSON(w, http.StatusOK, result)
+
+	if h.resultCallbackFn != nil {
+		h.resultCallbackFn(ctx, &params, result, buff)
+	}
+}
+
+// executes a graphql query, rejecting queries that exceed the limits
+func (h *Handler) execute(ctx context.Context, r *http.Request, opts *RequestOptions) (graphql.Params, *graphql.Result) {
 	params := graphql.Params{
 		Schema:         *h.Schema,
 		RequestString:  opts.Query,
@@ -141,7 +185,13 @@ func (h *Handler) ContextHandler(ctx context.Context, w http.ResponseWriter, r *
 	if h.rootObjectFn != nil {
 		params.RootObject = h.rootObjectFn(ctx, r)
 	}
-	result := graphql.Do(params)
+
+	var result *graphql.Result
+	if errs := h.limits.Validate(*h.Schema, opts.Query, opts.OperationName, opts.Variables); errs != nil {
+		result = &graphql.Result{Errors: errs}
+	} else {
+		result = graphql.Do(params)
+	}
 
 	if formatErrorFn := h.formatErrorFn; formatErrorFn != nil && len(result.Errors) > 0 {
 		formatted := make([]gqlerrors.FormattedError, len(result.Errors))
@@ -151,43 +201,24 @@ func (h *Handler) ContextHandler(ctx context.Context, w http.ResponseWriter, r *
 		result.Errors = formatted
 	}
 
-	if h.graphiqlConfig != nil {
-		acceptHeader := r.Header.Get("Accept")
-		_, raw := r.URL.Query()["raw"]
-		if !raw && !strings.Contains(acceptHeader, "application/json") && strings.Contains(acceptHeader, "text/html") {
-			renderGraphiQL(h.graphiqlConfig, w, r, params)
-			return
-		}
-	}
-
-	if h.playgroundConfig != nil && h.graphiqlConfig == nil {
-		acceptHeader := r.Header.Get("Accept")
-		_, raw := r.URL.Query()["raw"]
-		if !raw && !strings.Contains(acceptHeader, "application/json") && strings.Contains(acceptHeader, "text/html") {
-			renderPlayground(h.playgroundConfig, w, r)
-			return
-		}
-	}
+	return params, result
+}
 
+// writes a JSON response and returns the response body
+func (h *Handler) writeJSON(w http.ResponseWriter, status int, v interface{}) []byte {
 	// use proper JSON Header
 	w.Header().Add("Content-Type", "application/json; charset=utf-8")
 
 	var buff []byte
 	if h.pretty {
-		w.WriteHeader(http.StatusOK)
-		buff, _ = json.MarshalIndent(result, "", "\t")
-
-		w.Write(buff)
+		buff, _ = json.MarshalIndent(v, "", "\t")
 	} else {
-		w.WriteHeader(http.StatusOK)
-		buff, _ = json.Marshal(result)
-
-		w.Write(buff)
+		buff, _ = json.Marshal(v)
 	}
 
-	if h.resultCallbackFn != nil {
-		h.resultCallbackFn(ctx, &params, result, buff)
-	}
+	w.WriteHeader(status)
+	w.Write(buff)
+	return buff
 }
 
 // ServeHTTP provides an entrypoint into executing graphQL queries.
@@ -207,6 +238,8 @@ type Config struct {
 	RootObjectFn     RootObjectFn
 	ResultCallbackFn ResultCallbackFn
 	FormatErrorFn    func(err error) gqlerrors.FormattedError
+	Limits           *tools.QueryLimits
+	BatchConfig      *BatchConfig
 }
 
 // NewConfig returns a new default config
@@ -237,5 +270,7 @@ func New(p *Config) *Handler {
 		rootObjectFn:     p.RootObjectFn,
 		resultCallbackFn: p.ResultCallbackFn,
 		formatErrorFn:    p.FormatErrorFn,
+		limits:           p.Limits,
+		batchConfig:      p.BatchConfig,
 	}
 }
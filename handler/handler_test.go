@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+// creates a handler for a schema with hello and echo queries
+func newTestHandler(t *testing.T, config *Config) *Handler {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "world", nil
+					},
+				},
+				"echo": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"text": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Args["text"], nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Schema = &schema
+	return New(config)
+}
+
+// posts a JSON value to the handler and returns the response
+func postJSON(t *testing.T, h *Handler, v interface{}) *httptest.ResponseRecorder {
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	r.Header.Set("Content-Type", ContentTypeJSON)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+// asserts the JSON response body equals the expected JSON
+func assertJSON(t *testing.T, body []byte, expected string) {
+	t.Helper()
+	var actual, wanted interface{}
+	if err := json.Unmarshal(body, &actual); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", body, err)
+	}
+	if err := json.Unmarshal([]byte(expected), &wanted); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := json.Marshal(actual)
+	w, _ := json.Marshal(wanted)
+	if !bytes.Equal(a, w) {
+		t.Errorf("expected %s, got %s", w, a)
+	}
+}
+
+func TestHandlerQuery(t *testing.T) {
+	h := newTestHandler(t, &Config{})
+
+	w := postJSON(t, h, map[string]interface{}{
+		"query":     `query ($text: String) { hello echo(text: $text) }`,
+		"variables": map[string]interface{}{"text": "hi"},
+	})
+	assertJSON(t, w.Body.Bytes(), `{"data":{"hello":"world","echo":"hi"}}`)
+}
@@ -0,0 +1,30 @@
+package handler
+
+import (
+	"strings"
+	"testing"
+
+	tools "github.com/bhoriuchi/graphql-go-tools"
+)
+
+func TestLimits(t *testing.T) {
+	h := newTestHandler(t, &Config{
+		Limits: &tools.QueryLimits{MaxAliases: 1, MaxComplexity: 2},
+	})
+
+	tests := []struct {
+		query    string
+		contains string
+	}{
+		{query: `{ hello a: echo(text: "a") }`, contains: `{"data":{"a":"a","hello":"world"}}`},
+		{query: `{ a: hello b: hello }`, contains: "aliases"},
+		{query: `{ hello echo(text: "a") hi: hello }`, contains: "complexity"},
+	}
+
+	for _, test := range tests {
+		w := postJSON(t, h, map[string]interface{}{"query": test.query})
+		if !strings.Contains(w.Body.String(), test.contains) {
+			t.Errorf("%s: expected response containing %q, got %s", test.query, test.contains, w.Body.String())
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
@@ -173,7 +174,7 @@ func ReadSourceFiles(p string, recursive ...bool) (string, error) {
 			return "", err
 		}
 		for _, file := range files {
-			if err := readFunc(abs, file, nil); err != nil {
+			if err := readFunc(filepath.Join(abs, file.Name()), file, nil); err != nil {
 				return "", err
 			}
 		}
@@ -183,6 +184,15 @@ func ReadSourceFiles(p string, recursive ...bool) (string, error) {
 	return result, err
 }
 
+// DecodeArgs decodes resolver args into a struct using json tags
+func DecodeArgs(args map[string]interface{}, v interface{}) error {
+	data, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
 // UnaliasedPathArray gets the path array for a resolve function without aliases
 func UnaliasedPathArray(info graphql.ResolveInfo) []interface{} {
 	return unaliasedPathArray(info.Operation.GetSelectionSet(), info.Path.AsArray(), []interface{}{})
@@ -262,6 +272,24 @@ func GetPathFieldSubSelections(info graphql.ResolveInfo, field ...string) (names
 	return
 }
 
+// gets the deprecation reason from a deprecated directive or defaults to an empty string
+func getDeprecationReason(directives []*ast.Directive) string {
+	for _, dir := range directives {
+		if dir.Name.Value != graphql.DeprecatedDirective.Name {
+			continue
+		}
+		for _, arg := range dir.Arguments {
+			if arg.Name.Value == "reason" {
+				if reason, ok := arg.Value.GetValue().(string); ok && reason != "" {
+					return reason
+				}
+			}
+		}
+		return graphql.DefaultDeprecationReason
+	}
+	return ""
+}
+
 // determines if a field is hidden
 func isHiddenField(field *ast.FieldDefinition) bool {
 	hide := false
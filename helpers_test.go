@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadSourceFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "typedefs")
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"query.graphql":      "type Query { foo: Foo }",
+		"foo.gql":            "type Foo { name: String }",
+		"readme.md":          "not a type definition",
+		"nested/bar.graphql": "type Bar { name: String }",
+		"nested/ignored.txt": "not a type definition",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Error(err)
+			return
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Error(err)
+			return
+		}
+	}
+
+	// files in the directory are read from their own path
+	typeDefs, err := ReadSourceFiles(dir)
+	if err != nil {
+		t.Errorf("failed to read source files: %v", err)
+		return
+	}
+	if !strings.Contains(typeDefs, "type Query") || !strings.Contains(typeDefs, "type Foo") {
+		t.Errorf("expected type definitions from the directory, got %q", typeDefs)
+	}
+	if strings.Contains(typeDefs, "type Bar") || strings.Contains(typeDefs, "not a type definition") {
+		t.Errorf("expected only graphql files in the directory, got %q", typeDefs)
+	}
+
+	typeDefs, err = ReadSourceFiles(dir, true)
+	if err != nil {
+		t.Errorf("failed to read source files recursively: %v", err)
+		return
+	}
+	if !strings.Contains(typeDefs, "type Bar") {
+		t.Errorf("expected type definitions from nested directories, got %q", typeDefs)
+	}
+}
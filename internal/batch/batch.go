@@ -0,0 +1,84 @@
+// Package batch parses and executes batched GraphQL requests for the server
+// and handler packages
+package batch
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ReadRequest reads the JSON array body of a batched request into its items.
+// If the request is not a batch the body is restored and false is returned
+func ReadRequest(r *http.Request) ([]json.RawMessage, bool) {
+	if r.Method != http.MethodPost || r.Body == nil {
+		return nil, false
+	}
+
+	switch strings.Split(r.Header.Get("Content-Type"), ";")[0] {
+	case "application/graphql", "application/x-www-form-urlencoded":
+		return nil, false
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, false
+	}
+	r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
+
+	return Split(body)
+}
+
+// Split splits a JSON array body into its items. False is returned when the
+// body is not a JSON array
+func Split(body []byte) ([]json.RawMessage, bool) {
+	if trimmed := bytes.TrimSpace(body); len(trimmed) == 0 || trimmed[0] != '[' {
+		return nil, false
+	}
+
+	items := []json.RawMessage{}
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, false
+	}
+	return items, true
+}
+
+// Validate checks that a batch has at least one operation and no more than
+// maxSize operations, 0 is unlimited
+func Validate(size, maxSize int) error {
+	if size == 0 {
+		return fmt.Errorf("batch must contain at least one operation")
+	}
+	if maxSize > 0 && size > maxSize {
+		return fmt.Errorf("batch size %d exceeds the maximum of %d", size, maxSize)
+	}
+	return nil
+}
+
+// Run calls fn with the index of each operation in a batch, running at most
+// concurrency operations at once. Concurrency defaults to 1
+func Run(size, concurrency int, fn func(i int)) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+	wg := sync.WaitGroup{}
+
+	for i := 0; i < size; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,458 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// introspection type kinds
+const (
+	introspectionKindScalar      = "SCALAR"
+	introspectionKindObject      = "OBJECT"
+	introspectionKindInterface   = "INTERFACE"
+	introspectionKindUnion       = "UNION"
+	introspectionKindEnum        = "ENUM"
+	introspectionKindInputObject = "INPUT_OBJECT"
+	introspectionKindList        = "LIST"
+	introspectionKindNonNull     = "NON_NULL"
+)
+
+// the result of an introspection query with or without the data wrapper
+type introspectionResult struct {
+	Data *struct {
+		Schema *introspectionSchema `json:"__schema"`
+	} `json:"data"`
+	Schema *introspectionSchema `json:"__schema"`
+}
+
+type introspectionSchema struct {
+	QueryType        *introspectionTypeRef     `json:"queryType"`
+	MutationType     *introspectionTypeRef     `json:"mutationType"`
+	SubscriptionType *introspectionTypeRef     `json:"subscriptionType"`
+	Types            []*introspectionType      `json:"types"`
+	Directives       []*introspectionDirective `json:"directives"`
+}
+
+type introspectionTypeRef struct {
+	Kind   string                `json:"kind"`
+	Name   string                `json:"name"`
+	OfType *introspectionTypeRef `json:"ofType"`
+}
+
+type introspectionType struct {
+	Kind          string                     `json:"kind"`
+	Name          string                     `json:"name"`
+	Description   string                     `json:"description"`
+	Fields        []*introspectionField      `json:"fields"`
+	InputFields   []*introspectionInputValue `json:"inputFields"`
+	Interfaces    []*introspectionTypeRef    `json:"interfaces"`
+	EnumValues    []*introspectionEnumValue  `json:"enumValues"`
+	PossibleTypes []*introspectionTypeRef    `json:"possibleTypes"`
+}
+
+type introspectionField struct {
+	Name              string                     `json:"name"`
+	Description       string                     `json:"description"`
+	Args              []*introspectionInputValue `json:"args"`
+	Type              *introspectionTypeRef      `json:"type"`
+	IsDeprecated      bool                       `json:"isDeprecated"`
+	DeprecationReason string                     `json:"deprecationReason"`
+}
+
+type introspectionInputValue struct {
+	Name         string                `json:"name"`
+	Description  string                `json:"description"`
+	Type         *introspectionTypeRef `json:"type"`
+	DefaultValue *string               `json:"defaultValue"`
+}
+
+type introspectionEnumValue struct {
+	Name              string `json:"name"`
+	Description       string `json:"description"`
+	IsDeprecated      bool   `json:"isDeprecated"`
+	DeprecationReason string `json:"deprecationReason"`
+}
+
+type introspectionDirective struct {
+	Name        string                     `json:"name"`
+	Description string                     `json:"description"`
+	Locations   []string                   `json:"locations"`
+	Args        []*introspectionInputValue `json:"args"`
+}
+
+// BuildClientSchema is shorthand for BuildClientSchemaWithConfig without additional configuration
+func BuildClientSchema(introspection []byte) (graphql.Schema, error) {
+	return BuildClientSchemaWithConfig(context.Background(), introspection, ExecutableSchema{})
+}
+
+// BuildClientSchemaWithConfig builds a schema from the JSON result of an IntrospectionQuery.
+// The introspected types are used as the config TypeDefs so that Resolvers and SchemaDirectives
+// can be attached. Custom scalars without a resolver serialize and parse values as-is
+func BuildClientSchemaWithConfig(ctx context.Context, introspection []byte, config ExecutableSchema) (graphql.Schema, error) {
+	document, err := IntrospectionDocument(introspection)
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+
+	resolvers := map[string]interface{}{}
+	for name, resolver := range config.Resolvers {
+		resolvers[name] = resolver
+	}
+
+	for _, def := range document.Definitions {
+		if def.GetKind() != kinds.ScalarDefinition {
+			continue
+		}
+		name := def.(*ast.ScalarDefinition).Name.Value
+		if _, ok := resolvers[name]; !ok {
+			resolvers[name] = &ScalarResolver{
+				Serialize:    passthroughValue,
+				ParseValue:   passthroughValue,
+				ParseLiteral: parseLiteralValue,
+			}
+		}
+	}
+
+	config.TypeDefs = document
+	config.Resolvers = resolvers
+	return config.Make(ctx)
+}
+
+// IntrospectionDocument converts the JSON result of an IntrospectionQuery into a
+// type definitions document that can be used as the TypeDefs of an ExecutableSchema
+func IntrospectionDocument(introspection []byte) (*ast.Document, error) {
+	var result introspectionResult
+	if err := json.Unmarshal(introspection, &result); err != nil {
+		return nil, fmt.Errorf("invalid introspection result: %v", err)
+	}
+
+	schema := result.Schema
+	if result.Data != nil && result.Data.Schema != nil {
+		schema = result.Data.Schema
+	}
+	if schema == nil {
+		return nil, fmt.Errorf("invalid introspection result: no __schema found")
+	}
+	if schema.QueryType == nil {
+		return nil, fmt.Errorf("invalid introspection result: no queryType found")
+	}
+
+	definitions := []ast.Node{}
+	if def := introspectionSchemaDefinition(schema); def != nil {
+		definitions = append(definitions, def)
+	}
+
+	for _, directive := range schema.Directives {
+		if isSpecifiedDirective(directive.Name) {
+			continue
+		}
+		def, err := introspectionDirectiveDefinition(directive)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, def)
+	}
+
+	for _, t := range schema.Types {
+		if isSpecifiedScalar(t.Name) || isIntrospectionType(t.Name) {
+			continue
+		}
+		def, err := introspectionTypeDefinition(t)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, def)
+	}
+
+	return ast.NewDocument(&ast.Document{Definitions: definitions}), nil
+}
+
+// builds a schema definition if the root types do not use the default names
+func introspectionSchemaDefinition(schema *introspectionSchema) *ast.SchemaDefinition {
+	roots := []struct {
+		operation string
+		name      string
+		ref       *introspectionTypeRef
+	}{
+		{ast.OperationTypeQuery, DefaultRootQueryName, schema.QueryType},
+		{ast.OperationTypeMutation, DefaultRootMutationName, schema.MutationType},
+		{ast.OperationTypeSubscription, DefaultRootSubscriptionName, schema.SubscriptionType},
+	}
+
+	isDefault := true
+	operationTypes := []*ast.OperationTypeDefinition{}
+	for _, root := range roots {
+		if root.ref == nil || root.ref.Name == "" {
+			continue
+		}
+		if root.ref.Name != root.name {
+			isDefault = false
+		}
+		operationTypes = append(operationTypes, ast.NewOperationTypeDefinition(&ast.OperationTypeDefinition{
+			Operation: root.operation,
+			Type:      ast.NewNamed(&ast.Named{Name: astName(root.ref.Name)}),
+		}))
+	}
+
+	if isDefault {
+		return nil
+	}
+
+	return ast.NewSchemaDefinition(&ast.SchemaDefinition{
+		Directives:     []*ast.Directive{},
+		OperationTypes: operationTypes,
+	})
+}
+
+// builds a type definition from an introspected type
+func introspectionTypeDefinition(t *introspectionType) (ast.Node, error) {
+	switch t.Kind {
+	case introspectionKindScalar:
+		return ast.NewScalarDefinition(&ast.ScalarDefinition{
+			Name:        astName(t.Name),
+			Description: astDescription(t.Description),
+			Directives:  []*ast.Directive{},
+		}), nil
+
+	case introspectionKindObject:
+		fields, err := introspectionFieldDefinitions(t.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewObjectDefinition(&ast.ObjectDefinition{
+			Name:        astName(t.Name),
+			Description: astDescription(t.Description),
+			Interfaces:  introspectionNamedTypes(t.Interfaces),
+			Directives:  []*ast.Directive{},
+			Fields:      fields,
+		}), nil
+
+	case introspectionKindInterface:
+		fields, err := introspectionFieldDefinitions(t.Fields)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewInterfaceDefinition(&ast.InterfaceDefinition{
+			Name:        astName(t.Name),
+			Description: astDescription(t.Description),
+			Directives:  []*ast.Directive{},
+			Fields:      fields,
+		}), nil
+
+	case introspectionKindUnion:
+		return ast.NewUnionDefinition(&ast.UnionDefinition{
+			Name:        astName(t.Name),
+			Description: astDescription(t.Description),
+			Directives:  []*ast.Directive{},
+			Types:       introspectionNamedTypes(t.PossibleTypes),
+		}), nil
+
+	case introspectionKindEnum:
+		values := []*ast.EnumValueDefinition{}
+		for _, value := range t.EnumValues {
+			values = append(values, ast.NewEnumValueDefinition(&ast.EnumValueDefinition{
+				Name:        astName(value.Name),
+				Description: astDescription(value.Description),
+				Directives:  introspectionDeprecated(value.IsDeprecated, value.DeprecationReason),
+			}))
+		}
+		return ast.NewEnumDefinition(&ast.EnumDefinition{
+			Name:        astName(t.Name),
+			Description: astDescription(t.Description),
+			Directives:  []*ast.Directive{},
+			Values:      values,
+		}), nil
+
+	case introspectionKindInputObject:
+		fields, err := introspectionInputValueDefinitions(t.InputFields)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewInputObjectDefinition(&ast.InputObjectDefinition{
+			Name:        astName(t.Name),
+			Description: astDescription(t.Description),
+			Directives:  []*ast.Directive{},
+			Fields:      fields,
+		}), nil
+	}
+
+	return nil, fmt.Errorf("invalid introspection result: unknown kind %q for type %q", t.Kind, t.Name)
+}
+
+// builds a directive definition from an introspected directive
+func introspectionDirectiveDefinition(directive *introspectionDirective) (*ast.DirectiveDefinition, error) {
+	args, err := introspectionInputValueDefinitions(directive.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	locations := []*ast.Name{}
+	for _, loc := range directive.Locations {
+		locations = append(locations, astName(loc))
+	}
+
+	return ast.NewDirectiveDefinition(&ast.DirectiveDefinition{
+		Name:        astName(directive.Name),
+		Description: astDescription(directive.Description),
+		Arguments:   args,
+		Locations:   locations,
+	}), nil
+}
+
+// builds field definitions from introspected fields
+func introspectionFieldDefinitions(fields []*introspectionField) ([]*ast.FieldDefinition, error) {
+	defs := []*ast.FieldDefinition{}
+	for _, field := range fields {
+		fieldType, err := introspectionTypeRefToAST(field.Type)
+		if err != nil {
+			return nil, err
+		}
+		args, err := introspectionInputValueDefinitions(field.Args)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, ast.NewFieldDefinition(&ast.FieldDefinition{
+			Name:        astName(field.Name),
+			Description: astDescription(field.Description),
+			Arguments:   args,
+			Type:        fieldType,
+			Directives:  introspectionDeprecated(field.IsDeprecated, field.DeprecationReason),
+		}))
+	}
+	return defs, nil
+}
+
+// builds input value definitions from introspected input values
+func introspectionInputValueDefinitions(values []*introspectionInputValue) ([]*ast.InputValueDefinition, error) {
+	defs := []*ast.InputValueDefinition{}
+	for _, value := range values {
+		valueType, err := introspectionTypeRefToAST(value.Type)
+		if err != nil {
+			return nil, err
+		}
+
+		var defaultValue ast.Value
+		if value.DefaultValue != nil && *value.DefaultValue != "" {
+			if defaultValue, err = parseValueLiteral(*value.DefaultValue); err != nil {
+				return nil, fmt.Errorf("invalid introspection result: bad default value for %q: %v", value.Name, err)
+			}
+		}
+
+		defs = append(defs, ast.NewInputValueDefinition(&ast.InputValueDefinition{
+			Name:         astName(value.Name),
+			Description:  astDescription(value.Description),
+			Type:         valueType,
+			DefaultValue: defaultValue,
+			Directives:   []*ast.Directive{},
+		}))
+	}
+	return defs, nil
+}
+
+// builds named types from a list of introspected type references
+func introspectionNamedTypes(refs []*introspectionTypeRef) []*ast.Named {
+	named := []*ast.Named{}
+	for _, ref := range refs {
+		named = append(named, ast.NewNamed(&ast.Named{Name: astName(ref.Name)}))
+	}
+	return named
+}
+
+// builds an ast type from an introspected type reference
+func introspectionTypeRefToAST(ref *introspectionTypeRef) (ast.Type, error) {
+	if ref == nil {
+		return nil, fmt.Errorf("invalid introspection result: missing type reference")
+	}
+
+	switch ref.Kind {
+	case introspectionKindList:
+		t, err := introspectionTypeRefToAST(ref.OfType)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewList(&ast.List{Type: t}), nil
+	case introspectionKindNonNull:
+		t, err := introspectionTypeRefToAST(ref.OfType)
+		if err != nil {
+			return nil, err
+		}
+		return ast.NewNonNull(&ast.NonNull{Type: t}), nil
+	}
+
+	if ref.Name == "" {
+		return nil, fmt.Errorf("invalid introspection result: unnamed %s type reference", ref.Kind)
+	}
+	return ast.NewNamed(&ast.Named{Name: astName(ref.Name)}), nil
+}
+
+// creates a deprecated directive list for a deprecated field or enum value
+func introspectionDeprecated(isDeprecated bool, reason string) []*ast.Directive {
+	if !isDeprecated {
+		return []*ast.Directive{}
+	}
+	if reason == "" {
+		reason = graphql.DefaultDeprecationReason
+	}
+	return astDeprecated(reason)
+}
+
+// parses a graphql value literal such as an introspected default value
+func parseValueLiteral(value string) (ast.Value, error) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: "{ f(v: " + value + ") }",
+		Options: parser.ParseOptions{
+			NoLocation: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(doc.Definitions) == 1 {
+		if op, ok := doc.Definitions[0].(*ast.OperationDefinition); ok && op.SelectionSet != nil {
+			if len(op.SelectionSet.Selections) == 1 {
+				if field, ok := op.SelectionSet.Selections[0].(*ast.Field); ok && len(field.Arguments) == 1 {
+					return field.Arguments[0].Value, nil
+				}
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("invalid value literal %q", value)
+}
+
+// returns the value unchanged
+func passthroughValue(value interface{}) interface{} {
+	return value
+}
+
+// parses a literal into an untyped go value
+func parseLiteralValue(astValue ast.Value) interface{} {
+	switch value := astValue.(type) {
+	case *ast.ObjectValue:
+		obj := map[string]interface{}{}
+		for _, field := range value.Fields {
+			obj[field.Name.Value] = parseLiteralValue(field.Value)
+		}
+		return obj
+	case *ast.ListValue:
+		list := []interface{}{}
+		for _, item := range value.Values {
+			list = append(list, parseLiteralValue(item))
+		}
+		return list
+	case *ast.IntValue:
+		return graphql.Int.ParseLiteral(value)
+	case *ast.FloatValue:
+		return graphql.Float.ParseLiteral(value)
+	case *ast.StringValue, *ast.BooleanValue, *ast.EnumValue:
+		return value.GetValue()
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestBuildClientSchema(t *testing.T) {
+	typeDefs := `
+scalar JSON
+
+"""A user"""
+type User {
+	id: ID!
+	name: String @deprecated(reason: "use fullName")
+	fullName: String
+	role: Role
+	meta: JSON
+}
+
+enum Role {
+	ADMIN
+	USER
+}
+
+input UserFilter {
+	role: Role = USER
+	limit: Int = 10
+}
+
+type Query {
+	users(filter: UserFilter): [User!]!
+}`
+
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: typeDefs,
+		Resolvers: map[string]interface{}{
+			"JSON": &ScalarResolver{
+				Serialize: passthroughValue,
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: IntrospectionQuery,
+	})
+	if r.HasErrors() {
+		t.Error(r.Errors)
+		return
+	}
+
+	introspection, err := json.Marshal(r)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+
+	clientSchema, err := BuildClientSchema(introspection)
+	if err != nil {
+		t.Errorf("failed to build client schema: %v", err)
+		return
+	}
+
+	if expected, actual := PrintSchema(schema), PrintSchema(clientSchema); expected != actual {
+		t.Errorf("client schema does not match\nexpected:\n%s\nactual:\n%s", expected, actual)
+		return
+	}
+
+	clientSchema, err = BuildClientSchemaWithConfig(context.Background(), introspection, ExecutableSchema{
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"users": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							filter := p.Args["filter"].(map[string]interface{})
+							return []map[string]interface{}{
+								{"id": "1", "role": filter["role"], "meta": map[string]interface{}{"limit": filter["limit"]}},
+							}, nil
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to build client schema with config: %v", err)
+		return
+	}
+
+	r = graphql.Do(graphql.Params{
+		Schema:        clientSchema,
+		RequestString: `{ users(filter: {}) { id role meta } }`,
+	})
+	if r.HasErrors() {
+		t.Error(r.Errors)
+		return
+	}
+
+	user := r.Data.(map[string]interface{})["users"].([]interface{})[0].(map[string]interface{})
+	if user["role"] != "USER" {
+		t.Errorf("expected default role USER, got %v", user["role"])
+		return
+	}
+	if limit := user["meta"].(map[string]interface{})["limit"]; limit != 10 {
+		t.Errorf("expected default limit 10, got %v", limit)
+		return
+	}
+}
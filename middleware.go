@@ -0,0 +1,52 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// FieldMiddleware wraps the resolve function of every field in the schema.
+// The returned function should call Next to continue the resolver chain
+type FieldMiddleware func(p FieldMiddlewareParams) graphql.FieldResolveFn
+
+// FieldMiddlewareParams params
+type FieldMiddlewareParams struct {
+	Context    context.Context
+	Config     *graphql.Field
+	Node       *ast.FieldDefinition
+	ParentName string
+	ParentKind string
+	Next       graphql.FieldResolveFn
+}
+
+// applies the middleware to a field, the first middleware is the outermost
+func (c *registry) applyMiddleware(field *graphql.Field, definition *ast.FieldDefinition, kind, typeName string) {
+	if len(c.middleware) == 0 {
+		return
+	}
+
+	resolve := field.Resolve
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		if c.middleware[i] == nil {
+			continue
+		}
+		if next := c.middleware[i](FieldMiddlewareParams{
+			Context:    c.ctx,
+			Config:     field,
+			Node:       definition,
+			ParentName: typeName,
+			ParentKind: kind,
+			Next:       resolve,
+		}); next != nil {
+			resolve = next
+		}
+	}
+
+	field.Resolve = resolve
+}
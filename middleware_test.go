@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestMiddleware(t *testing.T) {
+	calls := []string{}
+
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+type Foo {
+	name: String
+}
+
+type Query {
+	foo: Foo
+}`,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"foo": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return map[string]interface{}{"name": "foo"}, nil
+						},
+					},
+				},
+			},
+		},
+		Middleware: []FieldMiddleware{
+			func(m FieldMiddlewareParams) graphql.FieldResolveFn {
+				return func(p graphql.ResolveParams) (interface{}, error) {
+					calls = append(calls, "outer:"+m.ParentName+"."+m.Node.Name.Value)
+					return m.Next(p)
+				}
+			},
+			func(m FieldMiddlewareParams) graphql.FieldResolveFn {
+				if m.ParentName != "Foo" {
+					return m.Next
+				}
+				return func(p graphql.ResolveParams) (interface{}, error) {
+					result, err := m.Next(p)
+					if err != nil {
+						return nil, err
+					}
+					return strings.ToUpper(result.(string)), nil
+				}
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ foo { name } }`,
+	})
+	if r.HasErrors() {
+		t.Error(r.Errors)
+		return
+	}
+
+	if name := r.Data.(map[string]interface{})["foo"].(map[string]interface{})["name"]; name != "FOO" {
+		t.Errorf("expected middleware to wrap default resolver, got %v", name)
+		return
+	}
+
+	if strings.Join(calls, ",") != "outer:Query.foo,outer:Foo.name" {
+		t.Errorf("unexpected middleware calls %v", calls)
+		return
+	}
+}
@@ -0,0 +1,223 @@
+package tools
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	mockTypeNameKey    = "__typename"
+	defaultMockListLen = 2
+)
+
+// MockFunc returns a mock value for a type. Mocks for object types
+// should return a map of field values, fields that are not in the
+// map will be mocked. Mocks for interfaces and unions must include
+// the __typename of the concrete object type in the returned map
+type MockFunc func(p graphql.ResolveParams) interface{}
+
+// MockOptions options for mocking a schema
+type MockOptions struct {
+	Mocks             map[string]MockFunc // mock functions by type name, overrides the default mocks
+	PreserveResolvers bool                // keeps the field resolvers defined in the ExecutableSchema Resolvers
+	ListLength        int                 // number of items in a mocked list, defaults to 2
+}
+
+// MockSchema is shorthand for MockSchemaWithContext with a background context
+func MockSchema(config ExecutableSchema, options MockOptions) (graphql.Schema, error) {
+	return MockSchemaWithContext(context.Background(), config, options)
+}
+
+// MockSchemaWithContext makes an executable schema where every field without a
+// resolver returns a generated value based on its type
+func MockSchemaWithContext(ctx context.Context, config ExecutableSchema, options MockOptions) (graphql.Schema, error) {
+	document, err := config.ConcatenateTypeDefs()
+	if err != nil {
+		return graphql.Schema{}, err
+	}
+
+	if options.ListLength <= 0 {
+		options.ListLength = defaultMockListLen
+	}
+
+	m := &mocker{options: options}
+	resolvers := map[string]interface{}{}
+
+	// copy the resolvers, dropping the field resolvers unless they are preserved
+	for name, resolver := range config.Resolvers {
+		switch r := resolver.(type) {
+		case *ObjectResolver:
+			if options.PreserveResolvers {
+				resolvers[name] = &ObjectResolver{IsTypeOf: r.IsTypeOf, Fields: copyFieldResolveMap(r.Fields)}
+			}
+		case *InterfaceResolver:
+			if options.PreserveResolvers {
+				resolvers[name] = &InterfaceResolver{ResolveType: r.ResolveType, Fields: copyFieldResolveMap(r.Fields)}
+			}
+		case *UnionResolver:
+			if options.PreserveResolvers {
+				resolvers[name] = &UnionResolver{ResolveType: r.ResolveType}
+			}
+		default:
+			resolvers[name] = resolver
+		}
+	}
+
+	for _, def := range document.Definitions {
+		switch node := def.(type) {
+		case *ast.ObjectDefinition:
+			m.addObjectMocks(resolvers, node)
+		case *ast.TypeExtensionDefinition:
+			m.addObjectMocks(resolvers, node.Definition)
+		case *ast.InterfaceDefinition:
+			r, ok := resolvers[node.Name.Value].(*InterfaceResolver)
+			if !ok {
+				r = &InterfaceResolver{}
+				resolvers[node.Name.Value] = r
+			}
+			r.ResolveType = mockResolveTypeFn(r.ResolveType)
+		case *ast.UnionDefinition:
+			r, ok := resolvers[node.Name.Value].(*UnionResolver)
+			if !ok {
+				r = &UnionResolver{}
+				resolvers[node.Name.Value] = r
+			}
+			r.ResolveType = mockResolveTypeFn(r.ResolveType)
+		case *ast.ScalarDefinition:
+			if _, ok := resolvers[node.Name.Value]; !ok {
+				resolvers[node.Name.Value] = &ScalarResolver{
+					Serialize:    passthroughValue,
+					ParseValue:   passthroughValue,
+					ParseLiteral: parseLiteralValue,
+				}
+			}
+		}
+	}
+
+	config.TypeDefs = document
+	config.Resolvers = resolvers
+	return config.Make(ctx)
+}
+
+// copies a field resolve map
+func copyFieldResolveMap(fields FieldResolveMap) FieldResolveMap {
+	copied := FieldResolveMap{}
+	for name, field := range fields {
+		copied[name] = field
+	}
+	return copied
+}
+
+type mocker struct {
+	options MockOptions
+}
+
+// adds a mock resolver to every object field without a resolver
+func (m *mocker) addObjectMocks(resolvers map[string]interface{}, definition *ast.ObjectDefinition) {
+	r, ok := resolvers[definition.Name.Value].(*ObjectResolver)
+	if !ok {
+		r = &ObjectResolver{}
+		resolvers[definition.Name.Value] = r
+	}
+	if r.Fields == nil {
+		r.Fields = FieldResolveMap{}
+	}
+
+	for _, field := range definition.Fields {
+		fieldResolve, ok := r.Fields[field.Name.Value]
+		switch {
+		case !ok:
+			r.Fields[field.Name.Value] = &FieldResolve{Resolve: m.resolveField}
+		case fieldResolve.Resolve == nil:
+			r.Fields[field.Name.Value] = &FieldResolve{Resolve: m.resolveField, Subscribe: fieldResolve.Subscribe}
+		}
+	}
+}
+
+// resolves a field from its source or a generated value
+func (m *mocker) resolveField(p graphql.ResolveParams) (interface{}, error) {
+	if p.Source != nil {
+		if value, err := graphql.DefaultResolveFn(p); err == nil && value != nil {
+			return value, nil
+		}
+	}
+	return m.mockValue(p.Info.ReturnType, p), nil
+}
+
+// generates a mock value for a type
+func (m *mocker) mockValue(t graphql.Type, p graphql.ResolveParams) interface{} {
+	switch tt := t.(type) {
+	case *graphql.NonNull:
+		return m.mockValue(tt.OfType, p)
+	case *graphql.List:
+		list := []interface{}{}
+		for i := 0; i < m.options.ListLength; i++ {
+			list = append(list, m.mockValue(tt.OfType, p))
+		}
+		return list
+	}
+
+	if mock, ok := m.options.Mocks[t.Name()]; ok && mock != nil {
+		return mock(p)
+	}
+
+	switch tt := t.(type) {
+	case *graphql.Object:
+		return map[string]interface{}{}
+	case *graphql.Interface, *graphql.Union:
+		possibleTypes := p.Info.Schema.PossibleTypes(tt.(graphql.Abstract))
+		if len(possibleTypes) == 0 {
+			return nil
+		}
+		object := possibleTypes[rand.Intn(len(possibleTypes))]
+		value, ok := m.mockValue(object, p).(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		value[mockTypeNameKey] = object.Name()
+		return value
+	case *graphql.Enum:
+		values := tt.Values()
+		if len(values) == 0 {
+			return nil
+		}
+		return values[rand.Intn(len(values))].Value
+	}
+
+	switch t.Name() {
+	case graphql.Int.Name():
+		return rand.Intn(200) - 100
+	case graphql.Float.Name():
+		return rand.Float64()*200 - 100
+	case graphql.Boolean.Name():
+		return rand.Intn(2) == 0
+	case graphql.ID.Name():
+		return uuid.New().String()
+	case graphql.DateTime.Name():
+		return time.Now()
+	}
+	return "Hello World"
+}
+
+// creates a ResolveTypeFn that resolves mocked values by their __typename
+// and falls back to the resolver defined for the type
+func mockResolveTypeFn(resolveType graphql.ResolveTypeFn) graphql.ResolveTypeFn {
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		if value, ok := p.Value.(map[string]interface{}); ok {
+			if name, ok := value[mockTypeNameKey].(string); ok {
+				if object, ok := p.Info.Schema.Type(name).(*graphql.Object); ok {
+					return object
+				}
+			}
+		}
+		if resolveType != nil {
+			return resolveType(p)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestMockSchema(t *testing.T) {
+	typeDefs := `
+enum Role {
+	ADMIN
+	USER
+}
+
+interface Node {
+	id: ID!
+}
+
+type User implements Node {
+	id: ID!
+	name: String
+	age: Int
+	role: Role!
+}
+
+type Post implements Node {
+	id: ID!
+	title: String!
+	author: User
+}
+
+union Result = User | Post
+
+type Query {
+	users: [User!]!
+	node: Node
+	search: [Result]
+	version: String
+}`
+
+	schema, err := MockSchema(ExecutableSchema{
+		TypeDefs: typeDefs,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"version": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return "1.0.0", nil
+						},
+					},
+				},
+			},
+		},
+	}, MockOptions{
+		PreserveResolvers: true,
+		ListLength:        3,
+		Mocks: map[string]MockFunc{
+			"User": func(p graphql.ResolveParams) interface{} {
+				return map[string]interface{}{"name": "mock user"}
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to mock schema: %v", err)
+		return
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `query {
+			version
+			users {
+				id
+				name
+				age
+				role
+			}
+			node {
+				id
+			}
+			search {
+				__typename
+				... on Post {
+					title
+					author {
+						name
+					}
+				}
+			}
+		}`,
+	})
+	if r.HasErrors() {
+		t.Error(r.Errors)
+		return
+	}
+
+	d := r.Data.(map[string]interface{})
+	if d["version"] != "1.0.0" {
+		t.Errorf("expected preserved resolver to return 1.0.0, got %v", d["version"])
+		return
+	}
+
+	users := d["users"].([]interface{})
+	if len(users) != 3 {
+		t.Errorf("expected 3 mocked users, got %d", len(users))
+		return
+	}
+
+	user := users[0].(map[string]interface{})
+	if user["name"] != "mock user" {
+		t.Errorf("expected custom mock name, got %v", user["name"])
+		return
+	}
+	if _, ok := user["age"].(int); !ok {
+		t.Errorf("expected mocked int age, got %v", user["age"])
+		return
+	}
+	if role := user["role"]; role != "ADMIN" && role != "USER" {
+		t.Errorf("expected mocked enum role, got %v", role)
+		return
+	}
+	if d["node"].(map[string]interface{})["id"] == nil {
+		t.Error("expected mocked interface id")
+		return
+	}
+	for _, result := range d["search"].([]interface{}) {
+		if typename := result.(map[string]interface{})["__typename"]; typename != "User" && typename != "Post" {
+			t.Errorf("expected mocked union member, got %v", typename)
+			return
+		}
+	}
+}
@@ -0,0 +1,454 @@
+package tools
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+	"github.com/graphql-go/graphql/language/printer"
+)
+
+// PrintSchema prints a schema as schema language. Types, fields, arguments,
+// and values are sorted by name so that the output is deterministic
+func PrintSchema(schema graphql.Schema) string {
+	return PrintSchemaWithDirectives(schema, nil)
+}
+
+// PrintSchemaWithDirectives prints a schema as schema language including the directives
+// applied in the type definitions document. The document is typically the document
+// returned by ExecutableSchema.Document() after the schema has been made
+func PrintSchemaWithDirectives(schema graphql.Schema, document *ast.Document) string {
+	doc := schemaDocument(schema)
+	if document != nil {
+		applyDocumentDirectives(doc, getAppliedDirectives(document))
+	}
+	return printSchemaDocument(doc)
+}
+
+// builds a sorted document from a schema excluding specified scalars,
+// introspection types, and builtin directives
+func schemaDocument(schema graphql.Schema) *ast.Document {
+	definitions := []ast.Node{}
+
+	if def := schemaDefinition(schema); def != nil {
+		definitions = append(definitions, def)
+	}
+
+	directives := append([]*graphql.Directive{}, schema.Directives()...)
+	sort.Slice(directives, func(i, j int) bool {
+		return directives[i].Name < directives[j].Name
+	})
+	for _, directive := range directives {
+		if !isBuiltinDirective(directive) {
+			definitions = append(definitions, astFromDirective(directive))
+		}
+	}
+
+	typeMap := schema.TypeMap()
+	for _, name := range sortedTypeNames(typeMap) {
+		if isSpecifiedScalar(name) || isIntrospectionType(name) {
+			continue
+		}
+		if def := astFromType(typeMap[name]); def != nil {
+			definitions = append(definitions, def)
+		}
+	}
+
+	return ast.NewDocument(&ast.Document{Definitions: definitions})
+}
+
+// builds a schema definition if the root types do not use the default names
+func schemaDefinition(schema graphql.Schema) *ast.SchemaDefinition {
+	roots := []struct {
+		operation string
+		name      string
+		object    *graphql.Object
+	}{
+		{ast.OperationTypeQuery, DefaultRootQueryName, schema.QueryType()},
+		{ast.OperationTypeMutation, DefaultRootMutationName, schema.MutationType()},
+		{ast.OperationTypeSubscription, DefaultRootSubscriptionName, schema.SubscriptionType()},
+	}
+
+	isDefault := true
+	operationTypes := []*ast.OperationTypeDefinition{}
+	for _, root := range roots {
+		if root.object == nil {
+			continue
+		}
+		if root.object.Name() != root.name {
+			isDefault = false
+		}
+		operationTypes = append(operationTypes, ast.NewOperationTypeDefinition(&ast.OperationTypeDefinition{
+			Operation: root.operation,
+			Type:      ast.NewNamed(&ast.Named{Name: astName(root.object.Name())}),
+		}))
+	}
+
+	if isDefault {
+		return nil
+	}
+
+	return ast.NewSchemaDefinition(&ast.SchemaDefinition{
+		Directives:     []*ast.Directive{},
+		OperationTypes: operationTypes,
+	})
+}
+
+// appliedDirectives maps a schema coordinate to the directives applied to it.
+// Coordinates take the form "Type", "Type.field", "Type.field.arg", "@directive.arg",
+// "Enum.VALUE", or "Input.field" and the schema definition uses "schema"
+type appliedDirectives map[string][]*ast.Directive
+
+// adds directives to a coordinate, the deprecated and hide directives
+// are excluded since they are represented in the schema itself
+func (c appliedDirectives) add(coordinate string, directives []*ast.Directive) {
+	for _, directive := range directives {
+		switch directive.Name.Value {
+		case graphql.DeprecatedDirective.Name, directiveHide:
+			continue
+		}
+		c[coordinate] = append(c[coordinate], directive)
+	}
+}
+
+// adds the directives applied to field definitions and their arguments
+func (c appliedDirectives) addFields(typeName string, fields []*ast.FieldDefinition) {
+	for _, field := range fields {
+		coordinate := typeName + "." + field.Name.Value
+		c.add(coordinate, field.Directives)
+		c.addInputValues(coordinate, field.Arguments)
+	}
+}
+
+// adds the directives applied to input value definitions
+func (c appliedDirectives) addInputValues(parent string, values []*ast.InputValueDefinition) {
+	for _, value := range values {
+		c.add(parent+"."+value.Name.Value, value.Directives)
+	}
+}
+
+// builds an index of the directives applied in a type definitions document
+func getAppliedDirectives(document *ast.Document) appliedDirectives {
+	applied := appliedDirectives{}
+
+	for _, def := range document.Definitions {
+		switch def.GetKind() {
+		case kinds.SchemaDefinition:
+			applied.add("schema", def.(*ast.SchemaDefinition).Directives)
+		case kinds.DirectiveDefinition:
+			node := def.(*ast.DirectiveDefinition)
+			applied.addInputValues("@"+node.Name.Value, node.Arguments)
+		case kinds.ScalarDefinition:
+			node := def.(*ast.ScalarDefinition)
+			applied.add(node.Name.Value, node.Directives)
+		case kinds.ObjectDefinition:
+			node := def.(*ast.ObjectDefinition)
+			applied.add(node.Name.Value, node.Directives)
+			applied.addFields(node.Name.Value, node.Fields)
+		case kinds.TypeExtensionDefinition:
+			node := def.(*ast.TypeExtensionDefinition).Definition
+			applied.add(node.Name.Value, node.Directives)
+			applied.addFields(node.Name.Value, node.Fields)
+		case kindScalarExtensionDefinition:
+			node := def.(*ScalarExtensionDefinition).Definition
+			applied.add(node.Name.Value, node.Directives)
+		case kindInterfaceExtensionDefinition:
+			node := def.(*InterfaceExtensionDefinition).Definition
+			applied.add(node.Name.Value, node.Directives)
+			applied.addFields(node.Name.Value, node.Fields)
+		case kindUnionExtensionDefinition:
+			node := def.(*UnionExtensionDefinition).Definition
+			applied.add(node.Name.Value, node.Directives)
+		case kindEnumExtensionDefinition:
+			node := def.(*EnumExtensionDefinition).Definition
+			applied.add(node.Name.Value, node.Directives)
+			for _, value := range node.Values {
+				applied.add(node.Name.Value+"."+value.Name.Value, value.Directives)
+			}
+		case kindInputObjectExtensionDefinition:
+			node := def.(*InputObjectExtensionDefinition).Definition
+			applied.add(node.Name.Value, node.Directives)
+			applied.addInputValues(node.Name.Value, node.Fields)
+		case kinds.InterfaceDefinition:
+			node := def.(*ast.InterfaceDefinition)
+			applied.add(node.Name.Value, node.Directives)
+			applied.addFields(node.Name.Value, node.Fields)
+		case kinds.UnionDefinition:
+			node := def.(*ast.UnionDefinition)
+			applied.add(node.Name.Value, node.Directives)
+		case kinds.EnumDefinition:
+			node := def.(*ast.EnumDefinition)
+			applied.add(node.Name.Value, node.Directives)
+			for _, value := range node.Values {
+				applied.add(node.Name.Value+"."+value.Name.Value, value.Directives)
+			}
+		case kinds.InputObjectDefinition:
+			node := def.(*ast.InputObjectDefinition)
+			applied.add(node.Name.Value, node.Directives)
+			applied.addInputValues(node.Name.Value, node.Fields)
+		}
+	}
+
+	return applied
+}
+
+// appends applied directives to the definitions of a document
+func applyDocumentDirectives(doc *ast.Document, applied appliedDirectives) {
+	applyFields := func(typeName string, fields []*ast.FieldDefinition) {
+		for _, field := range fields {
+			coordinate := typeName + "." + field.Name.Value
+			field.Directives = append(append([]*ast.Directive{}, applied[coordinate]...), field.Directives...)
+			for _, arg := range field.Arguments {
+				arg.Directives = append(arg.Directives, applied[coordinate+"."+arg.Name.Value]...)
+			}
+		}
+	}
+
+	for _, def := range doc.Definitions {
+		switch node := def.(type) {
+		case *ast.SchemaDefinition:
+			node.Directives = append(node.Directives, applied["schema"]...)
+		case *ast.DirectiveDefinition:
+			for _, arg := range node.Arguments {
+				arg.Directives = append(arg.Directives, applied["@"+node.Name.Value+"."+arg.Name.Value]...)
+			}
+		case *ast.ScalarDefinition:
+			node.Directives = append(node.Directives, applied[node.Name.Value]...)
+		case *ast.ObjectDefinition:
+			node.Directives = append(node.Directives, applied[node.Name.Value]...)
+			applyFields(node.Name.Value, node.Fields)
+		case *ast.InterfaceDefinition:
+			node.Directives = append(node.Directives, applied[node.Name.Value]...)
+			applyFields(node.Name.Value, node.Fields)
+		case *ast.UnionDefinition:
+			node.Directives = append(node.Directives, applied[node.Name.Value]...)
+		case *ast.EnumDefinition:
+			node.Directives = append(node.Directives, applied[node.Name.Value]...)
+			for _, value := range node.Values {
+				value.Directives = append(append([]*ast.Directive{}, applied[node.Name.Value+"."+value.Name.Value]...), value.Directives...)
+			}
+		case *ast.InputObjectDefinition:
+			node.Directives = append(node.Directives, applied[node.Name.Value]...)
+			for _, field := range node.Fields {
+				field.Directives = append(field.Directives, applied[node.Name.Value+"."+field.Name.Value]...)
+			}
+		}
+	}
+}
+
+// prints each definition of a document separated by a blank line
+func printSchemaDocument(doc *ast.Document) string {
+	printed := []string{}
+	for _, def := range doc.Definitions {
+		if str := printDefinition(def); str != "" {
+			printed = append(printed, str)
+		}
+	}
+	return strings.Join(printed, "\n\n") + "\n"
+}
+
+// prints a type system definition
+func printDefinition(def ast.Node) string {
+	switch node := def.(type) {
+	case *ast.SchemaDefinition:
+		lines := []string{}
+		for _, op := range node.OperationTypes {
+			lines = append(lines, "  "+op.Operation+": "+op.Type.Name.Value)
+		}
+		return "schema" + printDirectives(node.Directives) + " {\n" + strings.Join(lines, "\n") + "\n}"
+
+	case *ast.DirectiveDefinition:
+		locations := []string{}
+		for _, loc := range node.Locations {
+			locations = append(locations, loc.Value)
+		}
+		return printDescription(node.Description, "") +
+			"directive @" + node.Name.Value +
+			printArgs(node.Arguments, "") +
+			" on " + strings.Join(locations, " | ")
+
+	case *ast.ScalarDefinition:
+		return printDescription(node.Description, "") +
+			"scalar " + node.Name.Value +
+			printDirectives(node.Directives)
+
+	case *ast.ObjectDefinition:
+		return printDescription(node.Description, "") +
+			"type " + node.Name.Value +
+			printImplements(node.Interfaces) +
+			printDirectives(node.Directives) +
+			printFields(node.Fields)
+
+	case *ast.InterfaceDefinition:
+		return printDescription(node.Description, "") +
+			"interface " + node.Name.Value +
+			printDirectives(node.Directives) +
+			printFields(node.Fields)
+
+	case *ast.UnionDefinition:
+		types := []string{}
+		for _, t := range node.Types {
+			types = append(types, t.Name.Value)
+		}
+		return printDescription(node.Description, "") +
+			"union " + node.Name.Value +
+			printDirectives(node.Directives) +
+			" = " + strings.Join(types, " | ")
+
+	case *ast.EnumDefinition:
+		lines := []string{}
+		for _, value := range node.Values {
+			lines = append(lines, printDescription(value.Description, "  ")+
+				"  "+value.Name.Value+printDirectives(value.Directives))
+		}
+		return printDescription(node.Description, "") +
+			"enum " + node.Name.Value +
+			printDirectives(node.Directives) +
+			printBlock(lines)
+
+	case *ast.InputObjectDefinition:
+		lines := []string{}
+		for _, field := range node.Fields {
+			lines = append(lines, printInputValue(field, "  "))
+		}
+		return printDescription(node.Description, "") +
+			"input " + node.Name.Value +
+			printDirectives(node.Directives) +
+			printBlock(lines)
+	}
+
+	return ""
+}
+
+// prints a list of lines wrapped in braces
+func printBlock(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	return " {\n" + strings.Join(lines, "\n") + "\n}"
+}
+
+// prints the implemented interfaces
+func printImplements(ifaces []*ast.Named) string {
+	if len(ifaces) == 0 {
+		return ""
+	}
+	names := []string{}
+	for _, iface := range ifaces {
+		names = append(names, iface.Name.Value)
+	}
+	return " implements " + strings.Join(names, " & ")
+}
+
+// prints a block of field definitions
+func printFields(fields []*ast.FieldDefinition) string {
+	lines := []string{}
+	for _, field := range fields {
+		lines = append(lines, printDescription(field.Description, "  ")+
+			"  "+field.Name.Value+
+			printArgs(field.Arguments, "  ")+
+			": "+printType(field.Type)+
+			printDirectives(field.Directives))
+	}
+	return printBlock(lines)
+}
+
+// prints an argument list, arguments with descriptions are printed on separate lines
+func printArgs(args []*ast.InputValueDefinition, indent string) string {
+	if len(args) == 0 {
+		return ""
+	}
+
+	multiline := false
+	for _, arg := range args {
+		if arg.Description != nil && arg.Description.Value != "" {
+			multiline = true
+			break
+		}
+	}
+
+	printed := []string{}
+	for _, arg := range args {
+		if multiline {
+			printed = append(printed, printInputValue(arg, indent+"  "))
+		} else {
+			printed = append(printed, printInputValue(arg, ""))
+		}
+	}
+
+	if multiline {
+		return "(\n" + strings.Join(printed, "\n") + "\n" + indent + ")"
+	}
+	return "(" + strings.Join(printed, ", ") + ")"
+}
+
+// prints an input value definition
+func printInputValue(value *ast.InputValueDefinition, indent string) string {
+	str := printDescription(value.Description, indent) +
+		indent + value.Name.Value + ": " + printType(value.Type)
+	if value.DefaultValue != nil {
+		str += " = " + printValue(value.DefaultValue)
+	}
+	return str + printDirectives(value.Directives)
+}
+
+// prints a type reference
+func printType(t ast.Type) string {
+	switch tt := t.(type) {
+	case *ast.NonNull:
+		return printType(tt.Type) + "!"
+	case *ast.List:
+		return "[" + printType(tt.Type) + "]"
+	case *ast.Named:
+		return tt.Name.Value
+	}
+	return ""
+}
+
+// prints a list of applied directives
+func printDirectives(directives []*ast.Directive) string {
+	str := ""
+	for _, directive := range directives {
+		str += " @" + directive.Name.Value
+		if len(directive.Arguments) == 0 {
+			continue
+		}
+		args := []string{}
+		for _, arg := range directive.Arguments {
+			args = append(args, arg.Name.Value+": "+printValue(arg.Value))
+		}
+		str += "(" + strings.Join(args, ", ") + ")"
+	}
+	return str
+}
+
+// prints a value literal
+func printValue(value ast.Value) string {
+	return fmt.Sprintf("%v", printer.Print(value))
+}
+
+// prints a description as a block string followed by a new line
+func printDescription(description *ast.StringValue, indent string) string {
+	if description == nil || description.Value == "" {
+		return ""
+	}
+
+	value := strings.Replace(description.Value, `"""`, `\"""`, -1)
+	if !strings.Contains(value, "\n") && !strings.HasSuffix(value, `"`) && !strings.HasSuffix(value, `\`) {
+		return indent + `"""` + value + `"""` + "\n"
+	}
+
+	lines := []string{indent + `"""`}
+	for _, line := range strings.Split(value, "\n") {
+		if line == "" {
+			lines = append(lines, "")
+		} else {
+			lines = append(lines, indent+line)
+		}
+	}
+	lines = append(lines, indent+`"""`)
+	return strings.Join(lines, "\n") + "\n"
+}
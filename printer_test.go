@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPrintSchema(t *testing.T) {
+	config := ExecutableSchema{
+		TypeDefs: `
+directive @cache(maxAge: Int = 60) on OBJECT | FIELD_DEFINITION
+
+"""
+A named thing
+"""
+interface Named {
+	name: String!
+}
+
+enum Color {
+	RED
+	GREEN @deprecated(reason: "use RED")
+	BLUE
+}
+
+input Filter {
+	color: Color = RED
+	limit: Int = 10
+}
+
+"A foo"
+type Foo implements Named @cache(maxAge: 30) {
+	name: String!
+	colors(filter: Filter): [Color!] @cache
+	old: String @deprecated
+}
+
+type Query {
+	foos(
+		"the name"
+		name: String
+		first: Int = 5
+	): [Foo]
+}`,
+	}
+
+	schema, err := config.Make(nil)
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	expected := `directive @cache(maxAge: Int = 60) on OBJECT | FIELD_DEFINITION
+
+enum Color {
+  BLUE
+  GREEN @deprecated(reason: "use RED")
+  RED
+}
+
+"""The ` + "`DateTime`" + ` scalar type represents a DateTime. The DateTime is serialized as an RFC 3339 quoted string"""
+scalar DateTime
+
+input Filter {
+  color: Color = RED
+  limit: Int = 10
+}
+
+"""A foo"""
+type Foo implements Named @cache(maxAge: 30) {
+  colors(filter: Filter): [Color!] @cache
+  name: String!
+  old: String @deprecated
+}
+
+"""A named thing"""
+interface Named {
+  name: String!
+}
+
+type Query {
+  foos(
+    first: Int = 5
+    """the name"""
+    name: String
+  ): [Foo]
+}
+`
+
+	printed := PrintSchemaWithDirectives(schema, config.Document())
+	if printed != expected {
+		t.Errorf("unexpected schema output\n%s", printed)
+		return
+	}
+
+	// printing is deterministic
+	for i := 0; i < 5; i++ {
+		if again := PrintSchemaWithDirectives(schema, config.Document()); again != printed {
+			t.Errorf("schema output is not deterministic\n%s", again)
+			return
+		}
+	}
+}
+
+func TestPrintSchemaDeclaredDirectives(t *testing.T) {
+	// directives declared with the name of a builtin directive are printed
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+directive @auth(requires: Role!) on FIELD_DEFINITION
+directive @cost(weight: Int) on FIELD_DEFINITION
+
+enum Role {
+	ADMIN
+}
+
+type Query {
+	secret: String @auth(requires: ADMIN) @cost(weight: 2)
+}`,
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	sdl := PrintSchema(schema)
+	for _, expected := range []string{"directive @auth(requires: Role!) on FIELD_DEFINITION", "directive @cost(weight: Int) on FIELD_DEFINITION"} {
+		if !strings.Contains(sdl, expected) {
+			t.Errorf("expected printed schema to contain %q, got\n%s", expected, sdl)
+		}
+	}
+
+	// builtin directives are not printed
+	schema, err = MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+type Query {
+	secret: String @auth(requires: ["admin"]) @cost(complexity: 2)
+}`,
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+	if sdl := PrintSchema(schema); strings.Contains(sdl, "directive @") {
+		t.Errorf("expected builtin directives not to be printed, got\n%s", sdl)
+	}
+}
@@ -0,0 +1,165 @@
+package pubsub
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed is returned when using a closed pubsub
+var ErrClosed = errors.New("pubsub is closed")
+
+// InMemoryPubSub a PubSub that delivers payloads to subscribers in the same process
+type InMemoryPubSub struct {
+	mx          sync.RWMutex
+	bufferSize  int
+	closed      bool
+	done        chan struct{}
+	subscribers map[string]map[*subscriber]struct{}
+}
+
+type subscriber struct {
+	mx     sync.Mutex
+	ctx    context.Context
+	ch     chan interface{}
+	done   chan struct{}
+	once   sync.Once
+	topics []string
+	closed bool
+}
+
+// NewInMemoryPubSub creates an in memory pubsub. Each subscriber buffers up to
+// bufferSize payloads before publishing blocks
+func NewInMemoryPubSub(bufferSize int) *InMemoryPubSub {
+	if bufferSize < 0 {
+		bufferSize = 0
+	}
+	return &InMemoryPubSub{
+		bufferSize:  bufferSize,
+		done:        make(chan struct{}),
+		subscribers: map[string]map[*subscriber]struct{}{},
+	}
+}
+
+// Publish sends the payload to every subscriber of the topic
+func (ps *InMemoryPubSub) Publish(topic string, payload interface{}) error {
+	ps.mx.RLock()
+	if ps.closed {
+		ps.mx.RUnlock()
+		return ErrClosed
+	}
+	subs := make([]*subscriber, 0, len(ps.subscribers[topic]))
+	for sub := range ps.subscribers[topic] {
+		subs = append(subs, sub)
+	}
+	ps.mx.RUnlock()
+
+	for _, sub := range subs {
+		sub.send(payload)
+	}
+	return nil
+}
+
+// Subscribe subscribes to the topics until the context is done
+func (ps *InMemoryPubSub) Subscribe(ctx context.Context, topics ...string) (<-chan interface{}, error) {
+	sub := &subscriber{
+		ctx:    ctx,
+		ch:     make(chan interface{}, ps.bufferSize),
+		done:   make(chan struct{}),
+		topics: topics,
+	}
+
+	ps.mx.Lock()
+	if ps.closed {
+		ps.mx.Unlock()
+		return nil, ErrClosed
+	}
+	for _, topic := range topics {
+		subs, ok := ps.subscribers[topic]
+		if !ok {
+			subs = map[*subscriber]struct{}{}
+			ps.subscribers[topic] = subs
+		}
+		subs[sub] = struct{}{}
+	}
+	ps.mx.Unlock()
+
+	// closing the pubsub closes the subscription so the goroutine ends even
+	// when the context is never done
+	go func() {
+		select {
+		case <-ctx.Done():
+			ps.unsubscribe(sub)
+		case <-ps.done:
+		}
+	}()
+
+	return sub.ch, nil
+}
+
+// Close closes all subscriptions. Publishing or subscribing after closing returns ErrClosed
+func (ps *InMemoryPubSub) Close() error {
+	ps.mx.Lock()
+	if ps.closed {
+		ps.mx.Unlock()
+		return nil
+	}
+	ps.closed = true
+	close(ps.done)
+	subs := map[*subscriber]struct{}{}
+	for topic, topicSubs := range ps.subscribers {
+		for sub := range topicSubs {
+			subs[sub] = struct{}{}
+		}
+		delete(ps.subscribers, topic)
+	}
+	ps.mx.Unlock()
+
+	for sub := range subs {
+		sub.close()
+	}
+	return nil
+}
+
+// removes a subscriber from its topics and closes its channel
+func (ps *InMemoryPubSub) unsubscribe(sub *subscriber) {
+	ps.mx.Lock()
+	for _, topic := range sub.topics {
+		if subs, ok := ps.subscribers[topic]; ok {
+			delete(subs, sub)
+			if len(subs) == 0 {
+				delete(ps.subscribers, topic)
+			}
+		}
+	}
+	ps.mx.Unlock()
+
+	sub.close()
+}
+
+// sends a payload unless the subscription has ended
+func (sub *subscriber) send(payload interface{}) {
+	sub.mx.Lock()
+	defer sub.mx.Unlock()
+
+	if sub.closed {
+		return
+	}
+	select {
+	case sub.ch <- payload:
+	case <-sub.ctx.Done():
+	case <-sub.done:
+	}
+}
+
+// closes the subscriber channel once, releasing any blocked publishers first
+func (sub *subscriber) close() {
+	sub.once.Do(func() {
+		close(sub.done)
+
+		sub.mx.Lock()
+		defer sub.mx.Unlock()
+		sub.closed = true
+		close(sub.ch)
+	})
+}
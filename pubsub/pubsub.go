@@ -0,0 +1,112 @@
+package pubsub
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+)
+
+// PubSub publishes payloads to topics and subscribes to them. Subscriptions
+// end and their channels are closed when the context is done
+type PubSub interface {
+	Publish(topic string, payload interface{}) error
+	Subscribe(ctx context.Context, topics ...string) (<-chan interface{}, error)
+}
+
+// FilterFunc determines if a payload should be sent to a subscriber
+type FilterFunc func(p graphql.ResolveParams, payload interface{}) bool
+
+// TopicsFunc determines the topics to subscribe to from the field arguments
+type TopicsFunc func(p graphql.ResolveParams) ([]string, error)
+
+// SubscribeFn creates a field subscribe function that subscribes to the topics.
+// The subscription is cleaned up when the operation context is canceled
+func SubscribeFn(ps PubSub, topics ...string) graphql.FieldResolveFn {
+	return SubscribeTopicsFn(ps, func(p graphql.ResolveParams) ([]string, error) {
+		return topics, nil
+	})
+}
+
+// SubscribeTopicsFn creates a field subscribe function that subscribes to the
+// topics returned by the topics function
+func SubscribeTopicsFn(ps PubSub, topicsFn TopicsFunc) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		topics, err := topicsFn(p)
+		if err != nil {
+			return nil, err
+		}
+
+		ctx := resolveContext(p)
+		sub, err := ps.Subscribe(ctx, topics...)
+		if err != nil {
+			return nil, err
+		}
+
+		return forward(ctx, sub, nil), nil
+	}
+}
+
+// WithFilter wraps a subscribe function so that only the payloads accepted by
+// the filter are sent to the subscriber
+func WithFilter(subscribeFn graphql.FieldResolveFn, filter FilterFunc) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		result, err := subscribeFn(p)
+		if err != nil {
+			return nil, err
+		}
+
+		var sub <-chan interface{}
+		switch ch := result.(type) {
+		case chan interface{}:
+			sub = ch
+		case <-chan interface{}:
+			sub = ch
+		default:
+			if filter(p, result) {
+				return result, nil
+			}
+			return nil, nil
+		}
+
+		return forward(resolveContext(p), sub, func(payload interface{}) bool {
+			return filter(p, payload)
+		}), nil
+	}
+}
+
+// forwards payloads to the channel type expected by graphql subscriptions
+func forward(ctx context.Context, sub <-chan interface{}, accept func(payload interface{}) bool) chan interface{} {
+	ch := make(chan interface{})
+
+	go func() {
+		defer close(ch)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case payload, more := <-sub:
+				if !more {
+					return
+				}
+				if accept != nil && !accept(payload) {
+					continue
+				}
+				select {
+				case ch <- payload:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+// gets the context of a resolve
+func resolveContext(p graphql.ResolveParams) context.Context {
+	if p.Context == nil {
+		return context.Background()
+	}
+	return p.Context
+}
@@ -0,0 +1,193 @@
+package pubsub
+
+import (
+	"context"
+	"runtime"
+	"testing"
+	"time"
+
+	tools "github.com/bhoriuchi/graphql-go-tools"
+	"github.com/graphql-go/graphql"
+)
+
+func TestInMemoryPubSub(t *testing.T) {
+	ps := NewInMemoryPubSub(1)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sub, err := ps.Subscribe(ctx, "a", "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ps.Publish("a", 1)
+	if payload := <-sub; payload != 1 {
+		t.Errorf("expected 1, got %v", payload)
+	}
+	ps.Publish("b", 2)
+	if payload := <-sub; payload != 2 {
+		t.Errorf("expected 2, got %v", payload)
+	}
+
+	cancel()
+	select {
+	case _, more := <-sub:
+		if more {
+			t.Error("expected channel to be closed")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("subscription was not closed after cancel")
+	}
+
+	if err := ps.Publish("a", 3); err != nil {
+		t.Error(err)
+	}
+	if err := ps.Close(); err != nil {
+		t.Error(err)
+	}
+	if _, err := ps.Subscribe(context.Background(), "a"); err != ErrClosed {
+		t.Errorf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestSubscribeWithFilter(t *testing.T) {
+	ps := NewInMemoryPubSub(0)
+
+	schema, err := tools.MakeExecutableSchema(tools.ExecutableSchema{
+		TypeDefs: `
+type Message {
+	room: String
+	text: String
+}
+
+type Query {
+	version: String
+}
+
+type Subscription {
+	messageAdded(room: String!): Message
+}`,
+		Resolvers: tools.ResolverMap{
+			"Subscription": &tools.ObjectResolver{
+				Fields: tools.FieldResolveMap{
+					"messageAdded": &tools.FieldResolve{
+						Subscribe: WithFilter(SubscribeFn(ps, "messages"), func(p graphql.ResolveParams, payload interface{}) bool {
+							return payload.(map[string]interface{})["room"] == p.Args["room"]
+						}),
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return p.Source, nil
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	results := graphql.Subscribe(graphql.Params{
+		Schema:        schema,
+		RequestString: `subscription { messageAdded(room: "go") { text } }`,
+		Context:       ctx,
+	})
+
+	// wait for the subscription to be registered before publishing
+	for i := 0; i < 100; i++ {
+		ps.mx.RLock()
+		n := len(ps.subscribers["messages"])
+		ps.mx.RUnlock()
+		if n > 0 {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	go func() {
+		ps.Publish("messages", map[string]interface{}{"room": "js", "text": "skipped"})
+		ps.Publish("messages", map[string]interface{}{"room": "go", "text": "hello"})
+	}()
+
+	select {
+	case result := <-results:
+		if result.HasErrors() {
+			t.Fatal(result.Errors)
+		}
+		text := result.Data.(map[string]interface{})["messageAdded"].(map[string]interface{})["text"]
+		if text != "hello" {
+			t.Errorf("expected hello, got %v", text)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for result")
+	}
+}
+
+func TestInMemoryPubSubCloseEndsSubscriptions(t *testing.T) {
+	before := runtime.NumGoroutine()
+
+	ps := NewInMemoryPubSub(0)
+	subs := []<-chan interface{}{}
+	for i := 0; i < 10; i++ {
+		sub, err := ps.Subscribe(context.Background(), "a")
+		if err != nil {
+			t.Fatal(err)
+		}
+		subs = append(subs, sub)
+	}
+
+	if err := ps.Close(); err != nil {
+		t.Error(err)
+	}
+	if err := ps.Close(); err != nil {
+		t.Errorf("expected closing twice to succeed, got %v", err)
+	}
+	for _, sub := range subs {
+		if _, more := <-sub; more {
+			t.Error("expected subscription to be closed")
+		}
+	}
+
+	// subscriptions with a context that is never done do not leak goroutines
+	for i := 0; i < 100 && runtime.NumGoroutine() > before; i++ {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if n := runtime.NumGoroutine(); n > before {
+		t.Errorf("expected %d goroutines after close, got %d", before, n)
+	}
+}
+
+func TestWithFilterReceiveOnlyChannel(t *testing.T) {
+	ps := NewInMemoryPubSub(2)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the subscribe function returns the receive only channel of the pubsub
+	subscribeFn := WithFilter(func(p graphql.ResolveParams) (interface{}, error) {
+		return ps.Subscribe(p.Context, "numbers")
+	}, func(p graphql.ResolveParams, payload interface{}) bool {
+		return payload.(int)%2 == 0
+	})
+
+	result, err := subscribeFn(graphql.ResolveParams{Context: ctx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, ok := result.(chan interface{})
+	if !ok {
+		t.Fatalf("expected a channel, got %T", result)
+	}
+
+	ps.Publish("numbers", 1)
+	ps.Publish("numbers", 2)
+
+	select {
+	case payload := <-ch:
+		if payload != 2 {
+			t.Errorf("expected 2, got %v", payload)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for payload")
+	}
+}
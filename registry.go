@@ -15,19 +15,24 @@ var errUnresolvedDependencies = errors.New("unresolved dependencies")
 
 // registry the registry holds all of the types
 type registry struct {
-	ctx              context.Context
-	types            map[string]graphql.Type
-	directives       map[string]*graphql.Directive
-	schema           *graphql.Schema
-	resolverMap      resolverMap
-	directiveMap     SchemaDirectiveVisitorMap
-	schemaDirectives []*ast.Directive
-	document         *ast.Document
-	extensions       []graphql.Extension
-	unresolvedDefs   []ast.Node
-	maxIterations    int
-	iterations       int
-	dependencyMap    DependencyMap
+	ctx               context.Context
+	types             map[string]graphql.Type
+	directives        map[string]*graphql.Directive
+	pendingDirectives map[string]bool
+	schema            *graphql.Schema
+	resolverMap       resolverMap
+	directiveMap      SchemaDirectiveVisitorMap
+	schemaDirectives  []*ast.Directive
+	document          *ast.Document
+	extensions        []graphql.Extension
+	middleware        []FieldMiddleware
+	inputValidators   map[string]map[string][]InputValidator
+	appliedDirectives *AppliedDirectives
+	thunkErrors       []error
+	unresolvedDefs    []ast.Node
+	maxIterations     int
+	iterations        int
+	dependencyMap     DependencyMap
 }
 
 // newRegistry creates a new registry
@@ -36,6 +41,7 @@ func newRegistry(
 	resolvers map[string]interface{},
 	directiveMap SchemaDirectiveVisitorMap,
 	extensions []graphql.Extension,
+	middleware []FieldMiddleware,
 	document *ast.Document,
 ) (*registry, error) {
 	if ctx == nil {
@@ -56,16 +62,35 @@ func newRegistry(
 			"include":    graphql.IncludeDirective,
 			"skip":       graphql.SkipDirective,
 			"deprecated": graphql.DeprecatedDirective,
-			"hide":       HideDirective,
 		},
-		resolverMap:      resolverMap{},
-		directiveMap:     directiveMap,
-		schemaDirectives: []*ast.Directive{},
-		document:         document,
-		extensions:       extensions,
-		unresolvedDefs:   document.Definitions,
-		iterations:       0,
-		maxIterations:    len(document.Definitions),
+		pendingDirectives: map[string]bool{},
+		resolverMap:       resolverMap{},
+		directiveMap:      directiveMap,
+		schemaDirectives:  []*ast.Directive{},
+		document:          document,
+		extensions:        extensions,
+		middleware:        middleware,
+		inputValidators:   map[string]map[string][]InputValidator{},
+		appliedDirectives: newAppliedDirectives(),
+		unresolvedDefs:    document.Definitions,
+		iterations:        0,
+		maxIterations:     len(document.Definitions),
+	}
+
+	// the directives of this package are only defined when they are used or
+	// have a visitor so that they are not exposed by every schema
+	used := getDocumentDirectiveNames(document)
+	for _, directive := range toolDirectives {
+		if _, hasVisitor := directiveMap[directive.Name]; hasVisitor || used[directive.Name] {
+			r.directives[directive.Name] = directive
+		}
+	}
+
+	// directives declared in the document replace the builtin directives once built
+	for _, def := range document.Definitions {
+		if directive, ok := def.(*ast.DirectiveDefinition); ok {
+			r.pendingDirectives[directive.Name.Value] = true
+		}
 	}
 
 	// import each resolver to the correct location
@@ -78,6 +103,22 @@ func newRegistry(
 	return r, nil
 }
 
+// records an invalid directive error from a thunk since thunks cannot return
+// errors. Other errors are ignored so that thunks tolerate missing types
+func (c *registry) addThunkError(err error) {
+	if _, ok := err.(*directiveError); ok {
+		c.thunkErrors = append(c.thunkErrors, err)
+	}
+}
+
+// gets the first error returned while evaluating thunks
+func (c *registry) thunkError() error {
+	if len(c.thunkErrors) > 0 {
+		return c.thunkErrors[0]
+	}
+	return nil
+}
+
 // looks up a resolver by name or returns nil
 func (c *registry) getResolver(name string) Resolver {
 	if c.resolverMap != nil {
@@ -125,7 +166,7 @@ func (c *registry) getType(name string) (graphql.Type, error) {
 
 // Get gets a directive from the registry
 func (c *registry) getDirective(name string) (*graphql.Directive, error) {
-	if val, ok := c.directives[name]; ok {
+	if val, ok := c.directives[name]; ok && !c.pendingDirectives[name] {
 		return val, nil
 	}
 	return nil, errUnresolvedDependencies
@@ -214,7 +255,14 @@ func (c *registry) importResolver(name string, resolver interface{}) error {
 			c.resolverMap[name] = res
 		}
 	default:
-		return fmt.Errorf("invalid resolver type for %s", name)
+		// attempt to bind the methods of a go struct as field resolvers
+		bound, err := c.bindResolver(name, resolver)
+		if err != nil {
+			return err
+		}
+		if _, ok := c.resolverMap[name]; !ok {
+			c.resolverMap[name] = bound
+		}
 	}
 
 	return nil
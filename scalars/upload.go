@@ -0,0 +1,44 @@
+package scalars
+
+import (
+	"io"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Upload a file uploaded with a graphql multipart request
+type Upload struct {
+	File        io.Reader
+	Filename    string
+	ContentType string
+	Size        int64
+}
+
+// parses an upload variable value
+func parseValueUploadFn(value interface{}) interface{} {
+	switch v := value.(type) {
+	case *Upload:
+		return v
+	case Upload:
+		return &v
+	}
+	return nil
+}
+
+// ScalarUpload a scalar for files uploaded with the graphql multipart request spec.
+// Uploads can only be provided as variables and resolve to a *Upload. Add it to the
+// resolvers of an executable schema as "Upload" instead of declaring it in the type definitions
+var ScalarUpload = graphql.NewScalar(
+	graphql.ScalarConfig{
+		Name:        "Upload",
+		Description: "The `Upload` scalar type represents a file upload",
+		Serialize: func(value interface{}) interface{} {
+			return nil
+		},
+		ParseValue: parseValueUploadFn,
+		ParseLiteral: func(astValue ast.Value) interface{} {
+			return nil
+		},
+	},
+)
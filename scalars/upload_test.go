@@ -0,0 +1,70 @@
+package scalars
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestScalarUpload(t *testing.T) {
+	upload := &Upload{File: strings.NewReader("hello"), Filename: "hello.txt", Size: 5}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"upload": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"file": &graphql.ArgumentConfig{Type: ScalarUpload},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						file, ok := p.Args["file"].(*Upload)
+						if !ok {
+							return nil, nil
+						}
+						return file.Filename, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		query     string
+		variables map[string]interface{}
+		invalid   bool
+	}{
+		// uploads are provided as variables
+		{query: `query ($file: Upload) { upload(file: $file) }`, variables: map[string]interface{}{"file": upload}},
+		{query: `query ($file: Upload) { upload(file: $file) }`, variables: map[string]interface{}{"file": *upload}},
+		// literals and other values are rejected
+		{query: `{ upload(file: "hello.txt") }`, invalid: true},
+		{query: `query ($file: Upload) { upload(file: $file) }`, variables: map[string]interface{}{"file": "hello.txt"}, invalid: true},
+	}
+
+	for _, test := range tests {
+		result := graphql.Do(graphql.Params{Schema: schema, RequestString: test.query, VariableValues: test.variables})
+		if test.invalid {
+			if !result.HasErrors() {
+				t.Errorf("%s: expected the value to be rejected", test.query)
+			}
+			continue
+		}
+		if result.HasErrors() {
+			t.Errorf("%s: unexpected errors %v", test.query, result.Errors)
+			continue
+		}
+		if actual := result.Data.(map[string]interface{})["upload"]; actual != "hello.txt" {
+			t.Errorf("%s: expected hello.txt, got %v", test.query, actual)
+		}
+	}
+
+	if serialized := ScalarUpload.Serialize(upload); serialized != nil {
+		t.Errorf("expected uploads to serialize to nil, got %v", serialized)
+	}
+}
@@ -30,12 +30,15 @@ func MakeExecutableSchemaWithContext(ctx context.Context, config ExecutableSchem
 // this attempts to provide similar functionality to Apollo graphql-tools
 // https://www.apollographql.com/docs/graphql-tools/generate-schema
 type ExecutableSchema struct {
-	document         *ast.Document
-	TypeDefs         interface{}               // a string, []string, or func() []string
-	Resolvers        map[string]interface{}    // a map of Resolver, Directive, Scalar, Enum, Object, InputObject, Union, or Interface
-	SchemaDirectives SchemaDirectiveVisitorMap // Map of SchemaDirectiveVisitor
-	Extensions       []graphql.Extension       // GraphQL extensions
-	Debug            bool                      // Prints debug messages during compile
+	document             *ast.Document
+	TypeDefs             interface{}                   // a string, []string, func() []string, or *ast.Document
+	Resolvers            map[string]interface{}        // a map of Resolver, Directive, Scalar, Enum, Object, InputObject, Union, or Interface
+	SchemaDirectives     SchemaDirectiveVisitorMap     // Map of SchemaDirectiveVisitor
+	Extensions           []graphql.Extension           // GraphQL extensions
+	Middleware           []FieldMiddleware             // Middleware applied to the resolve function of every field
+	ExecutableDirectives ExecutableDirectiveVisitorMap // Map of directives executed when used in an operation
+	appliedDirectives    *AppliedDirectives
+	Debug                bool // Prints debug messages during compile
 }
 
 // Document returns the document
@@ -43,6 +46,11 @@ func (c *ExecutableSchema) Document() *ast.Document {
 	return c.document
 }
 
+// AppliedDirectives returns the index of directives used in the type definitions
+func (c *ExecutableSchema) AppliedDirectives() *AppliedDirectives {
+	return c.appliedDirectives
+}
+
 // Make creates a graphql schema config, this struct maintains intact the types and does not require the use of a non empty Query
 func (c *ExecutableSchema) Make(ctx context.Context) (graphql.Schema, error) {
 	// combine the TypeDefs
@@ -53,8 +61,14 @@ func (c *ExecutableSchema) Make(ctx context.Context) (graphql.Schema, error) {
 
 	c.document = document
 
+	// executable directives are applied after the middleware
+	middleware := c.Middleware
+	if len(c.ExecutableDirectives) > 0 {
+		middleware = append(append([]FieldMiddleware{}, c.Middleware...), executableDirectiveMiddleware(c.ExecutableDirectives))
+	}
+
 	// create a new registry
-	registry, err := newRegistry(ctx, c.Resolvers, c.SchemaDirectives, c.Extensions, document)
+	registry, err := newRegistry(ctx, c.Resolvers, c.SchemaDirectives, c.Extensions, middleware, document)
 	if err != nil {
 		return graphql.Schema{}, err
 	}
@@ -70,6 +84,14 @@ func (c *ExecutableSchema) Make(ctx context.Context) (graphql.Schema, error) {
 
 	// check if schema was created by definition
 	if registry.schema != nil {
+		if err := registry.thunkError(); err != nil {
+			return graphql.Schema{}, err
+		}
+		registry.appliedDirectives.addToSchema(registry.schema)
+		if len(c.ExecutableDirectives) > 0 {
+			addExecutableDirectiveCache(registry.schema)
+		}
+		c.appliedDirectives = registry.appliedDirectives
 		return *registry.schema, nil
 	}
 
@@ -99,6 +121,17 @@ func (c *ExecutableSchema) Make(ctx context.Context) (graphql.Schema, error) {
 		fmt.Println(string(j))
 	}
 
+	// thunks are evaluated when the schema is created
+	if err := registry.thunkError(); err != nil {
+		return graphql.Schema{}, err
+	}
+
+	registry.appliedDirectives.addToSchema(&schema)
+	if len(c.ExecutableDirectives) > 0 {
+		addExecutableDirectiveCache(&schema)
+	}
+	c.appliedDirectives = registry.appliedDirectives
+
 	// create a new schema
 	return schema, nil
 }
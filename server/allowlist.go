@@ -0,0 +1,87 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// Allow list errors
+var (
+	ErrPersistedOperationNotFound = &PersistedQueryError{
+		Message: "PersistedOperationNotFound",
+		Code:    "PERSISTED_OPERATION_NOT_FOUND",
+	}
+	ErrPersistedOperationRequired = &PersistedQueryError{
+		Message: "only persisted operations are allowed, provide the id of a registered operation",
+		Code:    "PERSISTED_OPERATION_REQUIRED",
+	}
+)
+
+// OperationManifest maps operation ids to their documents
+type OperationManifest map[string]string
+
+// AllowListOptions options for only executing operations registered in a manifest
+type AllowListOptions struct {
+	Manifest              OperationManifest
+	AllowArbitraryQueries bool // allows queries that are not in the manifest, useful during development
+}
+
+// manifest file format with a list of operations
+type operationManifestFile struct {
+	Operations []struct {
+		ID   string `json:"id"`
+		Body string `json:"body"`
+	} `json:"operations"`
+}
+
+// LoadOperationManifest loads a manifest from a JSON file. The file can either be an
+// object of operation ids to documents or an object with a list of operations
+// that each have an id and body
+func LoadOperationManifest(filename string) (OperationManifest, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	file := operationManifestFile{}
+	if err := json.Unmarshal(data, &file); err == nil && len(file.Operations) > 0 {
+		manifest := OperationManifest{}
+		for _, op := range file.Operations {
+			if op.ID == "" {
+				return nil, fmt.Errorf("invalid operation manifest %s: operation is missing an id", filename)
+			}
+			manifest[op.ID] = op.Body
+		}
+		return manifest, nil
+	}
+
+	manifest := OperationManifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid operation manifest %s: %v", filename, err)
+	}
+	return manifest, nil
+}
+
+// resolves the document of a request from the allow list. Requests with an id
+// use the registered document and requests with a query are rejected unless
+// arbitrary queries are allowed
+func (s *Server) resolveAllowedOperation(id, query string) (string, error) {
+	allowList := s.options.AllowList
+	if allowList == nil {
+		return query, nil
+	}
+
+	if id != "" {
+		document, ok := allowList.Manifest[id]
+		if !ok {
+			return "", ErrPersistedOperationNotFound
+		}
+		return document, nil
+	}
+
+	if query != "" && !allowList.AllowArbitraryQueries {
+		return "", ErrPersistedOperationRequired
+	}
+	return query, nil
+}
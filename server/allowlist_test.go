@@ -0,0 +1,102 @@
+package server
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAllowList(t *testing.T) {
+	manifest := OperationManifest{"hello": `{ hello }`}
+
+	tests := []struct {
+		name     string
+		options  *AllowListOptions
+		body     map[string]interface{}
+		contains string
+	}{
+		{
+			name:     "registered operation",
+			options:  &AllowListOptions{Manifest: manifest},
+			body:     map[string]interface{}{"id": "hello"},
+			contains: `{"data":{"hello":"world"}}`,
+		},
+		{
+			name:     "unknown operation",
+			options:  &AllowListOptions{Manifest: manifest},
+			body:     map[string]interface{}{"id": "unknown"},
+			contains: `"code":"PERSISTED_OPERATION_NOT_FOUND"`,
+		},
+		{
+			name:     "arbitrary query",
+			options:  &AllowListOptions{Manifest: manifest},
+			body:     map[string]interface{}{"query": `{ hello }`},
+			contains: `"code":"PERSISTED_OPERATION_REQUIRED"`,
+		},
+		{
+			name:     "allowed arbitrary query",
+			options:  &AllowListOptions{Manifest: manifest, AllowArbitraryQueries: true},
+			body:     map[string]interface{}{"query": `{ echo(text: "hi") }`},
+			contains: `{"data":{"echo":"hi"}}`,
+		},
+		{
+			// the allow list is checked before queries can be registered
+			name:    "persisted query",
+			options: &AllowListOptions{Manifest: manifest},
+			body: map[string]interface{}{
+				"query":      `{ hello }`,
+				"extensions": persistedQuery(queryHash(`{ hello }`), 1),
+			},
+			contains: `"code":"PERSISTED_OPERATION_REQUIRED"`,
+		},
+	}
+
+	for _, test := range tests {
+		server := newTestServer(t, &Options{
+			AllowList:        test.options,
+			PersistedQueries: &PersistedQueryOptions{},
+		})
+		if _, body := postJSON(t, server.URL, test.body); !strings.Contains(string(body), test.contains) {
+			t.Errorf("%s: expected response containing %s, got %s", test.name, test.contains, body)
+		}
+		server.Close()
+	}
+}
+
+func TestLoadOperationManifest(t *testing.T) {
+	dir, err := ioutil.TempDir("", "manifest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	expected := OperationManifest{"hello": `{ hello }`}
+	files := map[string]string{
+		"map.json":  `{"hello":"{ hello }"}`,
+		"list.json": `{"operations":[{"id":"hello","body":"{ hello }"}]}`,
+	}
+	for name, content := range files {
+		filename := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		manifest, err := LoadOperationManifest(filename)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !reflect.DeepEqual(manifest, expected) {
+			t.Errorf("%s: expected %v, got %v", name, expected, manifest)
+		}
+	}
+
+	// operations without an id are invalid
+	filename := filepath.Join(dir, "invalid.json")
+	ioutil.WriteFile(filename, []byte(`{"operations":[{"body":"{ hello }"}]}`), 0644)
+	if _, err := LoadOperationManifest(filename); err == nil {
+		t.Error("expected an error for an operation without an id")
+	}
+}
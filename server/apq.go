@@ -0,0 +1,180 @@
+package server
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+const (
+	persistedQueryExtension       = "persistedQuery"
+	persistedQueryVersion         = 1
+	defaultPersistedQueryCacheLen = 1000
+)
+
+// PersistedQueryError an error returned by automatic persisted queries.
+// The code is added to the extensions of the graphql error
+type PersistedQueryError struct {
+	Message string
+	Code    string
+}
+
+func (e *PersistedQueryError) Error() string {
+	return e.Message
+}
+
+// Extensions returns the error extensions
+func (e *PersistedQueryError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.Code}
+}
+
+// Persisted query errors
+var (
+	ErrPersistedQueryNotFound = &PersistedQueryError{
+		Message: "PersistedQueryNotFound",
+		Code:    "PERSISTED_QUERY_NOT_FOUND",
+	}
+	ErrPersistedQueryHashMismatch = &PersistedQueryError{
+		Message: "provided sha does not match query",
+		Code:    "BAD_REQUEST",
+	}
+	ErrPersistedQueryVersion = &PersistedQueryError{
+		Message: "Unsupported persisted query version",
+		Code:    "BAD_REQUEST",
+	}
+)
+
+// PersistedQueryStore stores queries by their sha256 hash
+type PersistedQueryStore interface {
+	Get(ctx context.Context, hash string) (string, bool)
+	Put(ctx context.Context, hash, query string)
+}
+
+// PersistedQueryOptions options for automatic persisted queries
+type PersistedQueryOptions struct {
+	Store PersistedQueryStore // defaults to an in memory LRU store of 1000 queries
+}
+
+// resolves the query of a request from the allow list and persisted queries.
+// The allow list is checked first so that queries cannot be registered as
+// persisted queries when only allow listed operations are permitted
+func (s *Server) resolveQuery(ctx context.Context, opts *RequestOptions) error {
+	query, err := s.resolveAllowedOperation(opts.ID, opts.Query)
+	if err != nil {
+		return err
+	}
+	opts.Query = query
+	return s.resolvePersistedQuery(ctx, opts)
+}
+
+// resolves the query of a request using the automatic persisted query protocol.
+// Requests with only a hash use the stored query and requests with a query
+// and hash store the query after verifying the hash
+func (s *Server) resolvePersistedQuery(ctx context.Context, opts *RequestOptions) error {
+	if s.options.PersistedQueries == nil {
+		return nil
+	}
+
+	pq, ok := opts.Extensions[persistedQueryExtension].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	if version, _ := pq["version"].(float64); version != persistedQueryVersion {
+		return ErrPersistedQueryVersion
+	}
+	hash, _ := pq["sha256Hash"].(string)
+	if hash == "" {
+		return nil
+	}
+
+	store := s.options.PersistedQueries.Store
+	if opts.Query == "" {
+		query, ok := store.Get(ctx, hash)
+		if !ok {
+			return ErrPersistedQueryNotFound
+		}
+		opts.Query = query
+		return nil
+	}
+
+	sum := sha256.Sum256([]byte(opts.Query))
+	if hex.EncodeToString(sum[:]) != strings.ToLower(hash) {
+		return ErrPersistedQueryHashMismatch
+	}
+	store.Put(ctx, hash, opts.Query)
+	return nil
+}
+
+// creates a result from a persisted query error
+func persistedQueryResult(err error) *graphql.Result {
+	return &graphql.Result{
+		Errors: []gqlerrors.FormattedError{
+			gqlerrors.FormatError(gqlerrors.NewError(err.Error(), nil, "", nil, nil, err)),
+		},
+	}
+}
+
+/**
+ * In memory LRU implementation of PersistedQueryStore
+ */
+
+type lruPersistedQueryStore struct {
+	mx      sync.Mutex
+	size    int
+	entries *list.List
+	queries map[string]*list.Element
+}
+
+type lruEntry struct {
+	hash  string
+	query string
+}
+
+// NewLRUPersistedQueryStore creates an in memory store that keeps the
+// most recently used queries up to the size
+func NewLRUPersistedQueryStore(size int) PersistedQueryStore {
+	if size <= 0 {
+		size = defaultPersistedQueryCacheLen
+	}
+	return &lruPersistedQueryStore{
+		size:    size,
+		entries: list.New(),
+		queries: map[string]*list.Element{},
+	}
+}
+
+func (c *lruPersistedQueryStore) Get(ctx context.Context, hash string) (string, bool) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	element, ok := c.queries[hash]
+	if !ok {
+		return "", false
+	}
+	c.entries.MoveToFront(element)
+	return element.Value.(*lruEntry).query, true
+}
+
+func (c *lruPersistedQueryStore) Put(ctx context.Context, hash, query string) {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	if element, ok := c.queries[hash]; ok {
+		element.Value.(*lruEntry).query = query
+		c.entries.MoveToFront(element)
+		return
+	}
+
+	c.queries[hash] = c.entries.PushFront(&lruEntry{hash: hash, query: query})
+	for c.entries.Len() > c.size {
+		oldest := c.entries.Back()
+		c.entries.Remove(oldest)
+		delete(c.queries, oldest.Value.(*lruEntry).hash)
+	}
+}
@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// gets the sha256 hash of a query
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// creates the persisted query extension
+func persistedQuery(hash string, version int) map[string]interface{} {
+	return map[string]interface{}{
+		persistedQueryExtension: map[string]interface{}{"version": version, "sha256Hash": hash},
+	}
+}
+
+func TestPersistedQueries(t *testing.T) {
+	server := newTestServer(t, &Options{PersistedQueries: &PersistedQueryOptions{}})
+	defer server.Close()
+
+	query := `{ hello }`
+	hash := queryHash(query)
+
+	tests := []struct {
+		name     string
+		body     map[string]interface{}
+		contains string
+	}{
+		{
+			name:     "unknown hash",
+			body:     map[string]interface{}{"extensions": persistedQuery(hash, 1)},
+			contains: `"code":"PERSISTED_QUERY_NOT_FOUND"`,
+		},
+		{
+			name:     "hash mismatch",
+			body:     map[string]interface{}{"query": `{ echo }`, "extensions": persistedQuery(hash, 1)},
+			contains: "provided sha does not match query",
+		},
+		{
+			name:     "unsupported version",
+			body:     map[string]interface{}{"query": query, "extensions": persistedQuery(hash, 2)},
+			contains: "Unsupported persisted query version",
+		},
+		{
+			name:     "register",
+			body:     map[string]interface{}{"query": query, "extensions": persistedQuery(hash, 1)},
+			contains: `{"data":{"hello":"world"}}`,
+		},
+		{
+			name:     "stored hash",
+			body:     map[string]interface{}{"extensions": persistedQuery(hash, 1)},
+			contains: `{"data":{"hello":"world"}}`,
+		},
+	}
+
+	for _, test := range tests {
+		if _, body := postJSON(t, server.URL, test.body); !strings.Contains(string(body), test.contains) {
+			t.Errorf("%s: expected response containing %s, got %s", test.name, test.contains, body)
+		}
+	}
+
+	// stored queries can be executed with GET
+	extensions := `{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}`
+	r, _ := http.NewRequest(http.MethodGet, server.URL+"?extensions="+url.QueryEscape(extensions), nil)
+	_, body := doRequest(t, r)
+	assertJSON(t, body, `{"data":{"hello":"world"}}`)
+}
+
+func TestLRUPersistedQueryStore(t *testing.T) {
+	ctx := context.Background()
+	store := NewLRUPersistedQueryStore(2)
+
+	store.Put(ctx, "a", "{ a }")
+	store.Put(ctx, "b", "{ b }")
+
+	// using a makes b the least recently used query
+	if query, ok := store.Get(ctx, "a"); !ok || query != "{ a }" {
+		t.Errorf("expected query a, got %q", query)
+	}
+	store.Put(ctx, "c", "{ c }")
+
+	if _, ok := store.Get(ctx, "b"); ok {
+		t.Error("expected query b to be evicted")
+	}
+	for _, hash := range []string{"a", "c"} {
+		if _, ok := store.Get(ctx, hash); !ok {
+			t.Errorf("expected query %s to be stored", hash)
+		}
+	}
+}
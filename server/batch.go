@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/bhoriuchi/graphql-go-tools/internal/batch"
+	"github.com/graphql-go/graphql"
+)
+
+// BatchOptions options for executing batched requests
+type BatchOptions struct {
+	MaxBatchSize   int // maximum number of operations in a batch, 0 is unlimited
+	MaxConcurrency int // number of operations executed concurrently, defaults to 1
+}
+
+// executes a single operation of a batch
+type executeFunc func(ctx context.Context, r *http.Request, opts *RequestOptions) (graphql.Params, *graphql.Result)
+
+// NewBatchRequestOptions Parses a http.Request with a JSON array body into a batch of
+// GraphQL request options. If the request is not a batch the body is restored and false is returned
+func NewBatchRequestOptions(r *http.Request) ([]*RequestOptions, bool) {
+	items, ok := batch.ReadRequest(r)
+	if !ok {
+		return nil, false
+	}
+
+	opts := make([]*RequestOptions, len(items))
+	for i, item := range items {
+		opts[i] = unmarshalRequestOptions(item)
+	}
+	return opts, true
+}
+
+// executes a batch of queries with the execute function and writes an array
+// of results with the media type
+func (s *Server) batchHandler(ctx context.Context, w http.ResponseWriter, r *http.Request, mediaType string, opts []*RequestOptions, execute executeFunc) {
+	if err := batch.Validate(len(opts), s.options.Batch.MaxBatchSize); err != nil {
+		s.writeResponse(w, http.StatusBadRequest, mediaType, errorResult(err))
+		return
+	}
+
+	params := make([]graphql.Params, len(opts))
+	results := make([]*graphql.Result, len(opts))
+	batch.Run(len(opts), s.options.Batch.MaxConcurrency, func(i int) {
+		params[i], results[i] = execute(ctx, r, opts[i])
+	})
+
+	s.writeResponse(w, http.StatusOK, mediaType, results)
+
+	if s.options.ResultCallbackFunc != nil {
+		for i, result := range results {
+			buff, _ := json.Marshal(result)
+			s.options.ResultCallbackFunc(ctx, &params[i], result, buff)
+		}
+	}
+}
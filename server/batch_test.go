@@ -0,0 +1,88 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestBatch(t *testing.T) {
+	server := newTestServer(t, &Options{
+		Batch: &BatchOptions{MaxBatchSize: 2, MaxConcurrency: 2},
+	})
+	defer server.Close()
+
+	// results are returned in the order of the operations
+	_, body := postJSON(t, server.URL, []map[string]interface{}{
+		{"query": `{ hello }`},
+		{"query": `query ($text: String) { echo(text: $text) }`, "variables": map[string]interface{}{"text": "hi"}},
+	})
+	assertJSON(t, body, `[{"data":{"hello":"world"}},{"data":{"echo":"hi"}}]`)
+
+	// batches larger than the maximum are rejected
+	res, body := postJSON(t, server.URL, []map[string]interface{}{
+		{"query": `{ hello }`}, {"query": `{ hello }`}, {"query": `{ hello }`},
+	})
+	if res.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), "exceeds the maximum of 2") {
+		t.Errorf("expected the batch size to be rejected, got %d %s", res.StatusCode, body)
+	}
+
+	// empty batches are rejected
+	res, _ = postJSON(t, server.URL, []map[string]interface{}{})
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status %d for an empty batch, got %d", http.StatusBadRequest, res.StatusCode)
+	}
+
+	// single operations are not batches
+	_, body = postJSON(t, server.URL, map[string]interface{}{"query": `{ hello }`})
+	assertJSON(t, body, `{"data":{"hello":"world"}}`)
+}
+
+func TestBatchDisabled(t *testing.T) {
+	server := newTestServer(t, &Options{})
+	defer server.Close()
+
+	_, body := postJSON(t, server.URL, []map[string]interface{}{{"query": `{ hello }`}})
+	if strings.HasPrefix(string(body), "[") {
+		t.Errorf("expected batches to be disabled, got %s", body)
+	}
+}
+
+func TestBatchSpecCompliant(t *testing.T) {
+	server := newTestServer(t, &Options{
+		SpecCompliant: true,
+		Batch:         &BatchOptions{},
+	})
+	defer server.Close()
+
+	// each operation follows the spec, invalid documents are request errors
+	res, body := postJSON(t, server.URL, []map[string]interface{}{
+		{"query": `{ hello }`},
+		{"query": `{ hello`},
+	})
+	if res.StatusCode != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, res.StatusCode)
+	}
+	if !strings.HasPrefix(string(body), `[{"data":{"hello":"world"}},{"data":null,"errors":[`) {
+		t.Errorf("unexpected batch result %s", body)
+	}
+
+	// operations with parameters of the wrong type are rejected
+	res, body = postJSON(t, server.URL, []map[string]interface{}{
+		{"query": `{ hello }`},
+		{"query": `{ hello }`, "variables": "invalid"},
+	})
+	if res.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), "invalid batch operation 1") {
+		t.Errorf("expected the batch to be rejected, got %d %s", res.StatusCode, body)
+	}
+
+	// the response uses the negotiated media type
+	r, _ := http.NewRequest(http.MethodPost, server.URL, strings.NewReader(`[{"query":"{ hello }"}]`))
+	r.Header.Set("Content-Type", ContentTypeJSON)
+	r.Header.Set("Accept", ContentTypeGraphQLResponseJSON)
+	res, body = doRequest(t, r)
+	if contentType := res.Header.Get("Content-Type"); !strings.HasPrefix(contentType, ContentTypeGraphQLResponseJSON) {
+		t.Errorf("expected content type %s, got %s", ContentTypeGraphQLResponseJSON, contentType)
+	}
+	assertJSON(t, body, `[{"data":{"hello":"world"}}]`)
+}
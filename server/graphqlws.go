@@ -7,13 +7,33 @@ import (
 	"github.com/bhoriuchi/graphql-go-tools/server/graphqlws"
 	"github.com/gorilla/websocket"
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
 )
 
+// creates the websocket upgrader from the options
+func newUpgrader(options *WSOptions) websocket.Upgrader {
+	upgrader := websocket.Upgrader{
+		CheckOrigin:  func(r *http.Request) bool { return true },
+		Subprotocols: []string{graphqlws.SubprotocolGraphQLTransportWS, graphqlws.SubprotocolGraphQLWS},
+	}
+	if options == nil {
+		return upgrader
+	}
+
+	upgrader.ReadBufferSize = options.ReadBufferSize
+	upgrader.WriteBufferSize = options.WriteBufferSize
+	upgrader.EnableCompression = options.EnableCompression
+	if options.CheckOrigin != nil {
+		upgrader.CheckOrigin = options.CheckOrigin
+	} else if len(options.AllowedOrigins) > 0 {
+		upgrader.CheckOrigin = graphqlws.AllowOrigins(options.AllowedOrigins)
+	}
+	return upgrader
+}
+
 func (s *Server) newGraphQLWSConnection(ctx context.Context, r *http.Request, ws *websocket.Conn) {
-	// Establish a GraphQL WebSocket connection
-	graphqlws.NewConnection(ws, graphqlws.ConnectionConfig{
-		Authenticate: s.options.WS.AuthenticateFunc,
-		Logger:       s.log,
+	config := graphqlws.ConnectionConfig{
+		Logger: s.log,
 		EventHandlers: graphqlws.ConnectionEventHandlers{
 			Close: func(conn graphqlws.Connection) {
 				s.log.Debugf("closing websocket: %s", conn.ID())
@@ -26,19 +46,45 @@ func (s *Server) newGraphQLWSConnection(ctx context.Context, r *http.Request, ws
 			) []error {
 				s.log.Debugf("start operations %s on connection %s", opID, conn.ID())
 
+				query, err := s.resolveAllowedOperation(data.ID, data.Query)
+				if err != nil {
+					return []error{err}
+				}
+				data.Query = query
+
+				if errs := s.options.Limits.Validate(s.schema, data.Query, data.OperationName, data.Variables); errs != nil {
+					return limitErrors(errs)
+				}
+
 				rootObject := map[string]interface{}{}
 				if s.options.RootValueFunc != nil {
 					rootObject = s.options.RootValueFunc(ctx, r)
 				}
-				ctx, cancelFunc := context.WithCancel(context.WithValue(context.Background(), ConnKey, conn))
-				resultChannel := graphql.Subscribe(graphql.Params{
+
+				// use the values of the authenticated connection context in the operation
+				ctx, cancelFunc := context.WithCancel(context.WithValue(graphqlws.DetachedContext(conn.Context()), ConnKey, conn))
+				params := graphql.Params{
 					Schema:         s.schema,
 					RequestString:  data.Query,
 					VariableValues: data.Variables,
 					OperationName:  data.OperationName,
 					Context:        ctx,
 					RootObject:     rootObject,
-				})
+				}
+
+				// queries and mutations send a single result. They execute in their
+				// own goroutine so the connection keeps reading messages meanwhile
+				var resultChannel chan *graphql.Result
+				if isSubscription(data.Query, data.OperationName) {
+					resultChannel = graphql.Subscribe(params)
+				} else {
+					ch := make(chan *graphql.Result, 1)
+					go func() {
+						ch <- graphql.Do(params)
+						close(ch)
+					}()
+					resultChannel = ch
+				}
 
 				s.mgr.Add(&ResultChan{
 					ch:         resultChannel,
@@ -56,6 +102,10 @@ func (s *Server) newGraphQLWSConnection(ctx context.Context, r *http.Request, ws
 							return
 						case res, more := <-resultChannel:
 							if !more {
+								s.mgr.Del(conn.ID(), opID)
+								if sender, ok := conn.(graphqlws.CompleteSender); ok {
+									sender.SendComplete(opID)
+								}
 								return
 							}
 
@@ -83,5 +133,30 @@ func (s *Server) newGraphQLWSConnection(ctx context.Context, r *http.Request, ws
 				s.mgr.Del(conn.ID(), opID)
 			},
 		},
-	})
+	}
+	if s.options.WS != nil {
+		config.Authenticate = s.options.WS.AuthenticateFunc
+		config.InitTimeout = s.options.WS.InitTimeout
+		config.KeepAlive = s.options.WS.KeepAlive
+		config.IdleTimeout = s.options.WS.IdleTimeout
+		config.MaxOperations = s.options.WS.MaxOperations
+		config.ReadLimit = s.options.WS.ReadLimit
+	}
+
+	// Establish a GraphQL WebSocket connection using the negotiated protocol
+	switch ws.Subprotocol() {
+	case graphqlws.SubprotocolGraphQLTransportWS:
+		graphqlws.NewTransportConnection(ws, config)
+	default:
+		graphqlws.NewConnection(ws, config)
+	}
+}
+
+// converts query limit errors to operation errors
+func limitErrors(errs []gqlerrors.FormattedError) []error {
+	opErrs := make([]error, len(errs))
+	for i, err := range errs {
+		opErrs[i] = err
+	}
+	return opErrs
 }
@@ -27,13 +27,13 @@ const (
 	gqlComplete            = "complete"
 	gqlStop                = "stop"
 
-	// Maximum size of incoming messages
-	readLimit = 4096
-
-	// Timeout for outgoing messages
-	writeTimeout = 10 * time.Second
+	// Default maximum size of incoming messages
+	defaultReadLimit = 4096
 )
 
+// Timeout for outgoing messages
+var writeTimeout = 10 * time.Second
+
 // InitMessagePayload defines the parameters of a connection
 // init message.
 type InitMessagePayload struct {
@@ -47,6 +47,7 @@ type StartMessagePayload struct {
 	Query         string                 `json:"query"`
 	Variables     map[string]interface{} `json:"variables"`
 	OperationName string                 `json:"operationName"`
+	ID            string                 `json:"id"`
 }
 
 // DataMessagePayload defines the result data of an operation.
@@ -102,6 +103,28 @@ type ConnectionConfig struct {
 	Logger        logger.Logger
 	Authenticate  AuthenticateFunc
 	EventHandlers ConnectionEventHandlers
+
+	// InitTimeout is the time allowed for a client to send the
+	// connection_init message. Defaults to 3 seconds for graphql-transport-ws
+	// connections, graphql-ws connections are only timed out when it is set
+	InitTimeout time.Duration
+
+	// KeepAlive is the interval at which keep-alive messages are sent once
+	// the connection is acknowledged; ka for graphql-ws and ping for
+	// graphql-transport-ws. Keep-alive messages are disabled when 0
+	KeepAlive time.Duration
+
+	// IdleTimeout closes connections that have no active operations and
+	// have not received a message for the duration, disabled when 0
+	IdleTimeout time.Duration
+
+	// MaxOperations is the maximum number of active operations on a
+	// connection, starting more closes the connection. Unlimited when 0
+	MaxOperations int
+
+	// ReadLimit is the maximum size in bytes of incoming messages,
+	// defaults to 4096
+	ReadLimit int64
 }
 
 // Connection is an interface to represent GraphQL WebSocket connections.
@@ -124,19 +147,31 @@ type Connection interface {
 	SendError(error)
 }
 
+// CompleteSender is implemented by connections that can notify the client
+// that an operation has completed and no more results will be sent.
+type CompleteSender interface {
+	SendComplete(string)
+}
+
 /**
  * The default implementation of the Connection interface.
  */
 
 type connection struct {
-	id         string
-	ws         *websocket.Conn
-	config     ConnectionConfig
-	logger     logger.Logger
-	outgoing   chan OperationMessage
-	closeMutex *sync.Mutex
-	closed     bool
-	context    context.Context
+	id           string
+	ws           *websocket.Conn
+	config       ConnectionConfig
+	logger       logger.Logger
+	outgoing     chan OperationMessage
+	closeMutex   *sync.Mutex
+	closed       bool
+	done         chan struct{}
+	context      context.Context
+	mx           sync.Mutex
+	initReceived bool
+	keepAlive    bool
+	lastActivity time.Time
+	operations   map[string]bool
 }
 
 func operationMessageForType(messageType string) OperationMessage {
@@ -154,10 +189,32 @@ func NewConnection(ws *websocket.Conn, config ConnectionConfig) Connection {
 	conn.ws = ws
 	conn.context = context.Background()
 	conn.config = config
+	if conn.config.ReadLimit <= 0 {
+		conn.config.ReadLimit = defaultReadLimit
+	}
 	conn.logger = config.Logger
 	conn.closed = false
 	conn.closeMutex = &sync.Mutex{}
 	conn.outgoing = make(chan OperationMessage)
+	conn.done = make(chan struct{})
+	conn.lastActivity = time.Now()
+	conn.operations = map[string]bool{}
+
+	if config.InitTimeout > 0 {
+		time.AfterFunc(config.InitTimeout, func() {
+			conn.mx.Lock()
+			initReceived := conn.initReceived
+			conn.mx.Unlock()
+			if !initReceived {
+				conn.closeWithCode(CloseInitTimeout, closeReasonInitTimeout)
+			}
+		})
+	}
+	if config.IdleTimeout > 0 {
+		go watchIdle(config.IdleTimeout, conn.done, conn.idleSince, func() {
+			conn.closeWithCode(websocket.CloseNormalClosure, closeReasonIdleTimeout)
+		})
+	}
 
 	go conn.writeLoop()
 	go conn.readLoop()
@@ -171,6 +228,8 @@ func (conn *connection) ID() string {
 }
 
 func (conn *connection) Context() context.Context {
+	conn.mx.Lock()
+	defer conn.mx.Unlock()
 	return conn.context
 }
 
@@ -182,44 +241,114 @@ func (conn *connection) SendData(opID string, data *DataMessagePayload) {
 	msg := operationMessageForType(gqlData)
 	msg.ID = opID
 	msg.Payload = data
-	conn.closeMutex.Lock()
-	if !conn.closed {
-		conn.outgoing <- msg
-	}
-	conn.closeMutex.Unlock()
+	conn.send(msg)
+}
+
+func (conn *connection) SendComplete(opID string) {
+	conn.removeOperation(opID)
+	msg := operationMessageForType(gqlComplete)
+	msg.ID = opID
+	conn.send(msg)
 }
 
 func (conn *connection) SendError(err error) {
 	msg := operationMessageForType(gqlError)
 	msg.Payload = err.Error()
-	conn.closeMutex.Lock()
-	if !conn.closed {
-		conn.outgoing <- msg
-	}
-	conn.closeMutex.Unlock()
+	conn.send(msg)
 }
 
 func (conn *connection) sendOperationErrors(opID string, errs []error) {
-	if conn.closed {
-		return
-	}
+	conn.removeOperation(opID)
 
 	msg := operationMessageForType(gqlError)
 	msg.ID = opID
 	msg.Payload = errs
-	conn.closeMutex.Lock()
-	if !conn.closed {
-		conn.outgoing <- msg
+	conn.send(msg)
+}
+
+// sends a message to the write loop unless the connection is closed
+func (conn *connection) send(msg OperationMessage) {
+	select {
+	case conn.outgoing <- msg:
+	case <-conn.done:
+	}
+}
+
+// adds an active operation and returns false if the maximum is exceeded
+func (conn *connection) addOperation(opID string) bool {
+	conn.mx.Lock()
+	defer conn.mx.Unlock()
+	if !conn.operations[opID] && conn.config.MaxOperations > 0 && len(conn.operations) >= conn.config.MaxOperations {
+		return false
+	}
+	conn.operations[opID] = true
+	return true
+}
+
+// removes an active operation
+func (conn *connection) removeOperation(opID string) {
+	conn.mx.Lock()
+	defer conn.mx.Unlock()
+	if conn.operations[opID] {
+		delete(conn.operations, opID)
+		conn.lastActivity = time.Now()
 	}
+}
+
+// returns the time the connection became idle or false while operations are active
+func (conn *connection) idleSince() (time.Time, bool) {
+	conn.mx.Lock()
+	defer conn.mx.Unlock()
+	return conn.lastActivity, len(conn.operations) == 0
+}
+
+// starts sending keep-alive messages once the connection is acknowledged
+func (conn *connection) startKeepAlive() {
+	if conn.config.KeepAlive <= 0 {
+		return
+	}
+
+	conn.mx.Lock()
+	started := conn.keepAlive
+	conn.keepAlive = true
+	conn.mx.Unlock()
+	if started {
+		return
+	}
+
+	conn.send(operationMessageForType(gqlConnectionKeepAlive))
+	go every(conn.config.KeepAlive, conn.done, func() {
+		conn.send(operationMessageForType(gqlConnectionKeepAlive))
+	})
+}
 
+// sends a close frame with the code and reason then closes the connection
+func (conn *connection) closeWithCode(code int, reason string) {
+	conn.closeMutex.Lock()
+	closed := conn.closed
 	conn.closeMutex.Unlock()
+	if closed {
+		return
+	}
+
+	conn.logger.Debugf("closing connection %s with code %d: %s", conn.id, code, reason)
+	conn.ws.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(writeTimeout),
+	)
+	conn.close()
 }
 
 func (conn *connection) close() {
-	// Close the write loop by closing the outgoing messages channels
+	// Close the write loop and release blocked senders by closing done
 	conn.closeMutex.Lock()
+	if conn.closed {
+		conn.closeMutex.Unlock()
+		return
+	}
 	conn.closed = true
-	close(conn.outgoing)
+	close(conn.done)
 	conn.closeMutex.Unlock()
 
 	// Notify event handlers
@@ -237,11 +366,12 @@ func (conn *connection) writeLoop() {
 	defer conn.ws.Close()
 
 	for {
-		msg, ok := <-conn.outgoing
-		// Close the write loop when the outgoing messages channel is closed;
-		// this will close the connection
-		if !ok {
+		// Close the write loop when the connection is closed
+		var msg OperationMessage
+		select {
+		case <-conn.done:
 			return
+		case msg = <-conn.outgoing:
 		}
 
 		// conn.logger.Debugf("send message: %s", msg.String())
@@ -252,6 +382,7 @@ func (conn *connection) writeLoop() {
 		// and the connection immediately
 		if err := conn.ws.WriteJSON(msg); err != nil {
 			conn.logger.Warnf("sending message failed: %s", err)
+			conn.close()
 			return
 		}
 	}
@@ -260,7 +391,7 @@ func (conn *connection) writeLoop() {
 func (conn *connection) readLoop() {
 	// Close the WebSocket connection when leaving the read loop
 	defer conn.ws.Close()
-	conn.ws.SetReadLimit(readLimit)
+	conn.ws.SetReadLimit(conn.config.ReadLimit)
 
 	for {
 		// Read the next message received from the client
@@ -268,7 +399,14 @@ func (conn *connection) readLoop() {
 		msg := OperationMessage{
 			Payload: &rawPayload,
 		}
-		err := conn.ws.ReadJSON(&msg)
+		data, err := ReadMessage(conn.ws, conn.config.ReadLimit)
+		if err == ErrMessageTooBig {
+			conn.closeWithCode(websocket.CloseMessageTooBig, "Message too big")
+			return
+		}
+		if err == nil {
+			err = json.Unmarshal(data, &msg)
+		}
 
 		// If this causes an error, close the connection and read loop immediately;
 		// see https://github.com/gorilla/websocket/blob/master/conn.go#L924 for
@@ -280,6 +418,9 @@ func (conn *connection) readLoop() {
 		}
 
 		// conn.logger.Debugf("received message (%s): %s", msg.ID, msg.Type)
+		conn.mx.Lock()
+		conn.lastActivity = time.Now()
+		conn.mx.Unlock()
 
 		switch msg.Type {
 		case gqlConnectionAuth:
@@ -293,15 +434,21 @@ func (conn *connection) readLoop() {
 					if err != nil {
 						msg := operationMessageForType(gqlConnectionError)
 						msg.Payload = fmt.Sprintf("Failed to authenticate user: %v", err)
-						conn.outgoing <- msg
+						conn.send(msg)
 					} else {
-						conn.context = ctx
+						conn.mx.Lock()
+						conn.context = authenticatedContext(ctx)
+						conn.mx.Unlock()
 					}
 				}
 			}
 
 		// When the GraphQL WS connection is initiated, send an ACK back
 		case gqlConnectionInit:
+			conn.mx.Lock()
+			conn.initReceived = true
+			conn.mx.Unlock()
+
 			data := map[string]interface{}{}
 			if err := json.Unmarshal(rawPayload, &data); err != nil {
 				conn.logger.Errorf("Invalid %s data: %v", msg.Type, err)
@@ -312,13 +459,17 @@ func (conn *connection) readLoop() {
 					if err != nil {
 						msg := operationMessageForType(gqlConnectionError)
 						msg.Payload = fmt.Sprintf("Failed to authenticate user: %v", err)
-						conn.outgoing <- msg
+						conn.send(msg)
 					} else {
-						conn.context = ctx
-						conn.outgoing <- operationMessageForType(gqlConnectionAck)
+						conn.mx.Lock()
+						conn.context = authenticatedContext(ctx)
+						conn.mx.Unlock()
+						conn.send(operationMessageForType(gqlConnectionAck))
+						conn.startKeepAlive()
 					}
 				} else {
-					conn.outgoing <- operationMessageForType(gqlConnectionAck)
+					conn.send(operationMessageForType(gqlConnectionAck))
+					conn.startKeepAlive()
 				}
 			}
 
@@ -328,6 +479,9 @@ func (conn *connection) readLoop() {
 				data := StartMessagePayload{}
 				if err := json.Unmarshal(rawPayload, &data); err != nil {
 					conn.SendError(errors.New("invalid GQL_START payload"))
+				} else if !conn.addOperation(msg.ID) {
+					conn.closeWithCode(websocket.ClosePolicyViolation, closeReasonTooManyOps)
+					return
 				} else {
 					errs := conn.config.EventHandlers.StartOperation(conn, msg.ID, &data)
 					if errs != nil {
@@ -338,6 +492,7 @@ func (conn *connection) readLoop() {
 
 		// Let event handlers deal with stopping operations
 		case gqlStop:
+			conn.removeOperation(msg.ID)
 			if conn.config.EventHandlers.StopOperation != nil {
 				conn.config.EventHandlers.StopOperation(conn, msg.ID)
 			}
@@ -357,3 +512,12 @@ func (conn *connection) readLoop() {
 		}
 	}
 }
+
+// authenticatedContext falls back to a background context when an
+// AuthenticateFunc returns no context
+func authenticatedContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
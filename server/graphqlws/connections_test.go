@@ -0,0 +1,226 @@
+package graphqlws
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhoriuchi/graphql-go-tools/server/logger"
+	"github.com/gorilla/websocket"
+)
+
+// starts a websocket server creating connections with newConn and dials it
+func dialTestConnection(t *testing.T, subprotocol string, newConn func(ws *websocket.Conn) Connection) (*websocket.Conn, func()) {
+	upgrader := websocket.Upgrader{Subprotocols: []string{subprotocol}}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ws, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		newConn(ws)
+	}))
+
+	dialer := websocket.Dialer{Subprotocols: []string{subprotocol}}
+	client, _, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	if err != nil {
+		server.Close()
+		t.Fatal(err)
+	}
+	return client, func() {
+		client.Close()
+		server.Close()
+	}
+}
+
+// reads messages until the connection is closed and returns the close code
+func readCloseCode(t *testing.T, client *websocket.Conn) int {
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := client.ReadMessage(); err != nil {
+			if e, ok := err.(*websocket.CloseError); ok {
+				return e.Code
+			}
+			t.Errorf("expected a close error, got %v", err)
+			return 0
+		}
+	}
+}
+
+func TestConnectionTimeoutDuringAuthentication(t *testing.T) {
+	tests := []struct {
+		name        string
+		messageType string
+		config      ConnectionConfig
+		code        int
+	}{
+		{
+			// the init timeout closes the connection while authenticating
+			name:        "init timeout",
+			messageType: gqlConnectionAuth,
+			config:      ConnectionConfig{InitTimeout: 50 * time.Millisecond},
+			code:        CloseInitTimeout,
+		},
+		{
+			// the idle timeout closes the connection while authenticating
+			name:        "idle timeout",
+			messageType: gqlConnectionInit,
+			config:      ConnectionConfig{IdleTimeout: 50 * time.Millisecond},
+			code:        websocket.CloseNormalClosure,
+		},
+	}
+
+	for _, test := range tests {
+		authenticated := make(chan struct{})
+		config := test.config
+		config.Logger = &logger.NoopLogger{}
+		config.Authenticate = func(data map[string]interface{}, conn Connection) (context.Context, error) {
+			defer close(authenticated)
+			time.Sleep(200 * time.Millisecond)
+			if test.messageType == gqlConnectionAuth {
+				return nil, errors.New("unauthorized")
+			}
+			return context.Background(), nil
+		}
+
+		client, cleanup := dialTestConnection(t, SubprotocolGraphQLWS, func(ws *websocket.Conn) Connection {
+			return NewConnection(ws, config)
+		})
+
+		if err := client.WriteJSON(OperationMessage{Type: test.messageType, Payload: map[string]interface{}{}}); err != nil {
+			t.Fatal(err)
+		}
+		if code := readCloseCode(t, client); code != test.code {
+			t.Errorf("%s: expected close code %d, got %d", test.name, test.code, code)
+		}
+
+		// the response to the message is not sent on the closed connection
+		select {
+		case <-authenticated:
+		case <-time.After(time.Second):
+			t.Fatalf("%s: timed out waiting for authentication", test.name)
+		}
+		time.Sleep(50 * time.Millisecond)
+		cleanup()
+	}
+}
+
+func TestConnectionAuthenticateWithoutContext(t *testing.T) {
+	tests := []struct {
+		subprotocol string
+		newConn     func(ws *websocket.Conn, config ConnectionConfig) Connection
+	}{
+		{subprotocol: SubprotocolGraphQLWS, newConn: NewConnection},
+		{subprotocol: SubprotocolGraphQLTransportWS, newConn: NewTransportConnection},
+	}
+
+	for _, test := range tests {
+		config := ConnectionConfig{
+			Logger: &logger.NoopLogger{},
+			Authenticate: func(data map[string]interface{}, conn Connection) (context.Context, error) {
+				return nil, nil
+			},
+		}
+
+		conns := make(chan Connection, 1)
+		client, cleanup := dialTestConnection(t, test.subprotocol, func(ws *websocket.Conn) Connection {
+			conn := test.newConn(ws, config)
+			conns <- conn
+			return conn
+		})
+
+		if err := client.WriteJSON(OperationMessage{Type: gqlConnectionInit, Payload: map[string]interface{}{}}); err != nil {
+			t.Fatal(err)
+		}
+		var msg map[string]interface{}
+		client.SetReadDeadline(time.Now().Add(2 * time.Second))
+		if err := client.ReadJSON(&msg); err != nil {
+			t.Fatal(err)
+		}
+		if msg["type"] != gqlConnectionAck {
+			t.Errorf("%s: expected %s, got %v", test.subprotocol, gqlConnectionAck, msg["type"])
+		}
+		if ctx := (<-conns).Context(); ctx == nil {
+			t.Errorf("%s: expected a background context when authenticate returns none", test.subprotocol)
+		}
+		cleanup()
+	}
+}
+
+func TestConnectionsSendComplete(t *testing.T) {
+	conns := []Connection{&connection{}, &transportConnection{}}
+	for _, conn := range conns {
+		if _, ok := conn.(CompleteSender); !ok {
+			t.Errorf("expected %T to implement CompleteSender", conn)
+		}
+	}
+}
+
+func TestConnectionWriteTimeout(t *testing.T) {
+	timeout := writeTimeout
+	writeTimeout = 50 * time.Millisecond
+	defer func() { writeTimeout = timeout }()
+
+	tests := []struct {
+		subprotocol string
+		newConn     func(ws *websocket.Conn, config ConnectionConfig) Connection
+		messages    []string
+	}{
+		{
+			subprotocol: SubprotocolGraphQLWS,
+			newConn:     NewConnection,
+			messages:    []string{`{"type":"connection_init","payload":{}}`},
+		},
+		{
+			subprotocol: SubprotocolGraphQLTransportWS,
+			newConn:     NewTransportConnection,
+			messages:    []string{`{"type":"connection_init"}`, `{"id":"1","type":"subscribe","payload":{"query":"{ hello }"}}`},
+		},
+	}
+
+	for _, test := range tests {
+		closed := make(chan struct{})
+		config := ConnectionConfig{
+			Logger:    &logger.NoopLogger{},
+			KeepAlive: 5 * time.Millisecond,
+			EventHandlers: ConnectionEventHandlers{
+				Close: func(conn Connection) { close(closed) },
+			},
+		}
+
+		// the client starts an operation then stops reading so its results
+		// fill the buffers until a write times out
+		client, cleanup := dialTestConnection(t, test.subprotocol, func(ws *websocket.Conn) Connection {
+			conn := test.newConn(ws, config)
+			go func() {
+				payload := &DataMessagePayload{Data: strings.Repeat("a", 1<<20)}
+				for {
+					select {
+					case <-closed:
+						return
+					default:
+						conn.SendData("1", payload)
+					}
+				}
+			}()
+			return conn
+		})
+		for _, msg := range test.messages {
+			if err := client.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// the failed write closes the connection and notifies the handlers
+		select {
+		case <-closed:
+		case <-time.After(5 * time.Second):
+			t.Errorf("%s: expected the connection to close after a write timeout", test.subprotocol)
+		}
+		cleanup()
+	}
+}
@@ -0,0 +1,22 @@
+package graphqlws
+
+import (
+	"context"
+	"time"
+)
+
+// detachedContext keeps the values of a context without its cancellation
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// DetachedContext returns a context with the values of ctx but without its
+// cancellation or deadline so that operations started with the values of a
+// request or an authenticated connection can outlive it
+func DetachedContext(ctx context.Context) context.Context {
+	return detachedContext{ctx}
+}
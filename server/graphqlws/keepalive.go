@@ -0,0 +1,53 @@
+package graphqlws
+
+import (
+	"time"
+)
+
+// Close reasons shared by both protocols
+const (
+	closeReasonInitTimeout = "Connection initialisation timeout"
+	closeReasonIdleTimeout = "Connection idle timeout"
+	closeReasonTooManyOps  = "Too many operations"
+)
+
+// runs fn every interval until done is closed
+func every(interval time.Duration, done <-chan struct{}, fn func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			fn()
+		}
+	}
+}
+
+// calls onIdle once the connection has been idle for the timeout. idleSince
+// returns the time the connection became idle or false while it is active
+func watchIdle(timeout time.Duration, done <-chan struct{}, idleSince func() (time.Time, bool), onIdle func()) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-timer.C:
+			since, idle := idleSince()
+			if !idle {
+				timer.Reset(timeout)
+				continue
+			}
+			remaining := timeout - time.Since(since)
+			if remaining <= 0 {
+				onIdle()
+				return
+			}
+			timer.Reset(remaining)
+		}
+	}
+}
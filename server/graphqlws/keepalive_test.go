@@ -0,0 +1,133 @@
+package graphqlws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bhoriuchi/graphql-go-tools/server/logger"
+	"github.com/gorilla/websocket"
+)
+
+// reads the type of the next message
+func readMessageType(t *testing.T, client *websocket.Conn) string {
+	t.Helper()
+	client.SetReadDeadline(time.Now().Add(2 * time.Second))
+	msg := map[string]interface{}{}
+	if err := client.ReadJSON(&msg); err != nil {
+		t.Fatal(err)
+	}
+	msgType, _ := msg["type"].(string)
+	return msgType
+}
+
+func TestKeepAlive(t *testing.T) {
+	tests := []struct {
+		subprotocol string
+		newConn     func(ws *websocket.Conn, config ConnectionConfig) Connection
+		keepAlive   string
+	}{
+		{subprotocol: SubprotocolGraphQLWS, newConn: NewConnection, keepAlive: gqlConnectionKeepAlive},
+		{subprotocol: SubprotocolGraphQLTransportWS, newConn: NewTransportConnection, keepAlive: gtwsPing},
+	}
+
+	for _, test := range tests {
+		config := ConnectionConfig{Logger: &logger.NoopLogger{}, KeepAlive: 20 * time.Millisecond}
+		client, cleanup := dialTestConnection(t, test.subprotocol, func(ws *websocket.Conn) Connection {
+			return test.newConn(ws, config)
+		})
+
+		if err := client.WriteJSON(OperationMessage{Type: gqlConnectionInit, Payload: map[string]interface{}{}}); err != nil {
+			t.Fatal(err)
+		}
+		if msgType := readMessageType(t, client); msgType != gqlConnectionAck {
+			t.Errorf("%s: expected %s, got %s", test.subprotocol, gqlConnectionAck, msgType)
+		}
+
+		// keep-alive messages are sent repeatedly after the ack
+		for i := 0; i < 2; i++ {
+			if msgType := readMessageType(t, client); msgType != test.keepAlive {
+				t.Errorf("%s: expected %s, got %s", test.subprotocol, test.keepAlive, msgType)
+			}
+		}
+		cleanup()
+	}
+}
+
+func TestTimeouts(t *testing.T) {
+	tests := []struct {
+		name        string
+		subprotocol string
+		newConn     func(ws *websocket.Conn, config ConnectionConfig) Connection
+		config      ConnectionConfig
+		init        bool
+		code        int
+		reason      string
+	}{
+		{
+			name:        "graphql-ws init timeout",
+			subprotocol: SubprotocolGraphQLWS,
+			newConn:     NewConnection,
+			config:      ConnectionConfig{InitTimeout: 50 * time.Millisecond},
+			code:        CloseInitTimeout,
+			reason:      closeReasonInitTimeout,
+		},
+		{
+			name:        "graphql-transport-ws init timeout",
+			subprotocol: SubprotocolGraphQLTransportWS,
+			newConn:     NewTransportConnection,
+			config:      ConnectionConfig{InitTimeout: 50 * time.Millisecond},
+			code:        CloseInitTimeout,
+			reason:      closeReasonInitTimeout,
+		},
+		{
+			name:        "graphql-ws idle timeout",
+			subprotocol: SubprotocolGraphQLWS,
+			newConn:     NewConnection,
+			config:      ConnectionConfig{IdleTimeout: 50 * time.Millisecond},
+			init:        true,
+			code:        websocket.CloseNormalClosure,
+			reason:      closeReasonIdleTimeout,
+		},
+		{
+			name:        "graphql-transport-ws idle timeout",
+			subprotocol: SubprotocolGraphQLTransportWS,
+			newConn:     NewTransportConnection,
+			config:      ConnectionConfig{IdleTimeout: 50 * time.Millisecond},
+			init:        true,
+			code:        websocket.CloseNormalClosure,
+			reason:      closeReasonIdleTimeout,
+		},
+	}
+
+	for _, test := range tests {
+		config := test.config
+		config.Logger = &logger.NoopLogger{}
+		client, cleanup := dialTestConnection(t, test.subprotocol, func(ws *websocket.Conn) Connection {
+			return test.newConn(ws, config)
+		})
+
+		if test.init {
+			if err := client.WriteJSON(OperationMessage{Type: gqlConnectionInit, Payload: map[string]interface{}{}}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		client.SetReadDeadline(time.Now().Add(2 * time.Second))
+		var closeErr *websocket.CloseError
+		for closeErr == nil {
+			_, _, err := client.ReadMessage()
+			if err == nil {
+				continue
+			}
+			e, ok := err.(*websocket.CloseError)
+			if !ok {
+				t.Fatalf("%s: expected a close error, got %v", test.name, err)
+			}
+			closeErr = e
+		}
+		if closeErr.Code != test.code || closeErr.Text != test.reason {
+			t.Errorf("%s: expected close %d %q, got %d %q", test.name, test.code, test.reason, closeErr.Code, closeErr.Text)
+		}
+		cleanup()
+	}
+}
@@ -0,0 +1,31 @@
+package graphqlws
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+
+	"github.com/gorilla/websocket"
+)
+
+// ErrMessageTooBig is returned when a message exceeds the read limit
+var ErrMessageTooBig = errors.New("message exceeds the read limit")
+
+// ReadMessage reads the next message of at most limit bytes. The websocket read
+// limit only applies to the frames received, so messages compressed with
+// permessage-deflate are limited after they are decompressed as well
+func ReadMessage(ws *websocket.Conn, limit int64) ([]byte, error) {
+	_, r, err := ws.NextReader()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, ErrMessageTooBig
+	}
+	return data, nil
+}
@@ -0,0 +1,24 @@
+package graphqlws
+
+import (
+	"net/http"
+	"strings"
+)
+
+// AllowOrigins returns a websocket upgrader CheckOrigin function that checks
+// the origin of a request against the allowed origins. Requests without an
+// origin do not come from browsers and are allowed. * allows all origins
+func AllowOrigins(origins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true
+		}
+		for _, allowed := range origins {
+			if allowed == "*" || strings.EqualFold(allowed, origin) {
+				return true
+			}
+		}
+		return false
+	}
+}
@@ -0,0 +1,30 @@
+package graphqlws
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAllowOrigins(t *testing.T) {
+	tests := []struct {
+		origins []string
+		origin  string
+		allowed bool
+	}{
+		{origins: []string{"https://example.com"}, origin: "", allowed: true},
+		{origins: []string{"https://example.com"}, origin: "https://example.com", allowed: true},
+		{origins: []string{"https://example.com"}, origin: "https://EXAMPLE.com", allowed: true},
+		{origins: []string{"https://example.com"}, origin: "https://evil.com", allowed: false},
+		{origins: []string{"https://example.com", "*"}, origin: "https://evil.com", allowed: true},
+	}
+
+	for _, test := range tests {
+		r := httptest.NewRequest("GET", "/graphql", nil)
+		if test.origin != "" {
+			r.Header.Set("Origin", test.origin)
+		}
+		if allowed := AllowOrigins(test.origins)(r); allowed != test.allowed {
+			t.Errorf("origin %q with %v: expected %t, got %t", test.origin, test.origins, test.allowed, allowed)
+		}
+	}
+}
@@ -0,0 +1,417 @@
+package graphqlws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/bhoriuchi/graphql-go-tools/server/logger"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Subprotocols supported by the server
+const (
+	SubprotocolGraphQLWS          = "graphql-ws"
+	SubprotocolGraphQLTransportWS = "graphql-transport-ws"
+)
+
+// Close codes used by the graphql-transport-ws protocol
+const (
+	CloseInvalidMessage         = 4400
+	CloseUnauthorized           = 4401
+	CloseForbidden              = 4403
+	CloseInitTimeout            = 4408
+	CloseSubscriberAlreadyExist = 4409
+	CloseTooManyInitRequests    = 4429
+)
+
+const (
+	// Constants for graphql-transport-ws message types
+	gtwsConnectionInit = "connection_init"
+	gtwsConnectionAck  = "connection_ack"
+	gtwsPing           = "ping"
+	gtwsPong           = "pong"
+	gtwsSubscribe      = "subscribe"
+	gtwsNext           = "next"
+	gtwsError          = "error"
+	gtwsComplete       = "complete"
+
+	// Time allowed for the client to send the connection_init message
+	defaultInitTimeout = 3 * time.Second
+
+	// Maximum length of a close reason in a close frame
+	maxCloseReasonLength = 123
+)
+
+// transportMessage represents a graphql-transport-ws message
+type transportMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// outgoing graphql-transport-ws message
+type transportOutgoingMessage struct {
+	ID      string      `json:"id,omitempty"`
+	Type    string      `json:"type"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// executionResult the payload of a next message
+type executionResult struct {
+	Data   interface{}                `json:"data"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+/**
+ * The graphql-transport-ws implementation of the Connection interface.
+ */
+
+type transportConnection struct {
+	id           string
+	ws           *websocket.Conn
+	config       ConnectionConfig
+	logger       logger.Logger
+	outgoing     chan transportOutgoingMessage
+	closeMutex   *sync.Mutex
+	closed       bool
+	done         chan struct{}
+	context      context.Context
+	mx           sync.Mutex
+	initReceived bool
+	acknowledged bool
+	lastActivity time.Time
+	operations   map[string]bool
+}
+
+// NewTransportConnection establishes a GraphQL WebSocket connection using the
+// graphql-transport-ws protocol. Clients must send a connection_init message
+// within the configured InitTimeout before any operations can be started
+func NewTransportConnection(ws *websocket.Conn, config ConnectionConfig) Connection {
+	conn := new(transportConnection)
+	conn.id = uuid.New().String()
+	conn.ws = ws
+	conn.context = context.Background()
+	conn.config = config
+	if conn.config.ReadLimit <= 0 {
+		conn.config.ReadLimit = defaultReadLimit
+	}
+	conn.logger = config.Logger
+	conn.closed = false
+	conn.closeMutex = &sync.Mutex{}
+	conn.outgoing = make(chan transportOutgoingMessage)
+	conn.done = make(chan struct{})
+	conn.lastActivity = time.Now()
+	conn.operations = map[string]bool{}
+
+	initTimeout := config.InitTimeout
+	if initTimeout <= 0 {
+		initTimeout = defaultInitTimeout
+	}
+	time.AfterFunc(initTimeout, func() {
+		conn.mx.Lock()
+		initReceived := conn.initReceived
+		conn.mx.Unlock()
+		if !initReceived {
+			conn.closeWithCode(CloseInitTimeout, closeReasonInitTimeout)
+		}
+	})
+	if config.IdleTimeout > 0 {
+		go watchIdle(config.IdleTimeout, conn.done, conn.idleSince, func() {
+			conn.closeWithCode(websocket.CloseNormalClosure, closeReasonIdleTimeout)
+		})
+	}
+
+	go conn.writeLoop()
+	go conn.readLoop()
+	conn.logger.Infof("Created connection")
+
+	return conn
+}
+
+func (conn *transportConnection) ID() string {
+	return conn.id
+}
+
+func (conn *transportConnection) Context() context.Context {
+	conn.mx.Lock()
+	defer conn.mx.Unlock()
+	return conn.context
+}
+
+func (conn *transportConnection) WS() *websocket.Conn {
+	return conn.ws
+}
+
+func (conn *transportConnection) SendData(opID string, data *DataMessagePayload) {
+	conn.mx.Lock()
+	active := conn.operations[opID]
+	conn.mx.Unlock()
+	if !active {
+		return
+	}
+
+	result := executionResult{Data: data.Data}
+	if len(data.Errors) > 0 {
+		result.Errors = gqlerrors.FormatErrors(data.Errors...)
+	}
+	conn.send(transportOutgoingMessage{ID: opID, Type: gtwsNext, Payload: result})
+}
+
+func (conn *transportConnection) SendComplete(opID string) {
+	if conn.removeOperation(opID) {
+		conn.send(transportOutgoingMessage{ID: opID, Type: gtwsComplete})
+	}
+}
+
+// SendError closes the connection since the protocol has no connection level errors
+func (conn *transportConnection) SendError(err error) {
+	conn.closeWithCode(CloseInvalidMessage, err.Error())
+}
+
+func (conn *transportConnection) sendOperationErrors(opID string, errs []error) {
+	if conn.removeOperation(opID) {
+		conn.send(transportOutgoingMessage{ID: opID, Type: gtwsError, Payload: gqlerrors.FormatErrors(errs...)})
+	}
+}
+
+// sends a message to the write loop unless the connection is closed
+func (conn *transportConnection) send(msg transportOutgoingMessage) {
+	select {
+	case conn.outgoing <- msg:
+	case <-conn.done:
+	}
+}
+
+// adds an operation and returns false with a close code if it already
+// exists or the maximum number of operations is exceeded
+func (conn *transportConnection) addOperation(opID string) (int, bool) {
+	conn.mx.Lock()
+	defer conn.mx.Unlock()
+	if conn.operations[opID] {
+		return CloseSubscriberAlreadyExist, false
+	}
+	if conn.config.MaxOperations > 0 && len(conn.operations) >= conn.config.MaxOperations {
+		return websocket.ClosePolicyViolation, false
+	}
+	conn.operations[opID] = true
+	return 0, true
+}
+
+// removes an operation and returns false if it did not exist
+func (conn *transportConnection) removeOperation(opID string) bool {
+	conn.mx.Lock()
+	defer conn.mx.Unlock()
+	if !conn.operations[opID] {
+		return false
+	}
+	delete(conn.operations, opID)
+	conn.lastActivity = time.Now()
+	return true
+}
+
+// returns the time the connection became idle or false while operations are active
+func (conn *transportConnection) idleSince() (time.Time, bool) {
+	conn.mx.Lock()
+	defer conn.mx.Unlock()
+	return conn.lastActivity, len(conn.operations) == 0
+}
+
+// sends a close frame with the code and reason then closes the connection
+func (conn *transportConnection) closeWithCode(code int, reason string) {
+	if len(reason) > maxCloseReasonLength {
+		reason = reason[:maxCloseReasonLength]
+	}
+
+	conn.closeMutex.Lock()
+	closed := conn.closed
+	conn.closeMutex.Unlock()
+	if closed {
+		return
+	}
+
+	conn.logger.Debugf("closing connection %s with code %d: %s", conn.id, code, reason)
+	conn.ws.WriteControl(
+		websocket.CloseMessage,
+		websocket.FormatCloseMessage(code, reason),
+		time.Now().Add(writeTimeout),
+	)
+	conn.close()
+}
+
+func (conn *transportConnection) close() {
+	// Close the write loop and release blocked senders by closing done
+	conn.closeMutex.Lock()
+	if conn.closed {
+		conn.closeMutex.Unlock()
+		return
+	}
+	conn.closed = true
+	close(conn.done)
+	conn.closeMutex.Unlock()
+
+	// Notify event handlers
+	if conn.config.EventHandlers.Close != nil {
+		conn.config.EventHandlers.Close(conn)
+	}
+
+	conn.logger.Infof("closed connection")
+}
+
+func (conn *transportConnection) writeLoop() {
+	// Close the WebSocket connection when leaving the write loop;
+	// this ensures the read loop is also terminated and the connection
+	// closed cleanly
+	defer conn.ws.Close()
+
+	for {
+		// Close the write loop when the connection is closed
+		var msg transportOutgoingMessage
+		select {
+		case <-conn.done:
+			return
+		case msg = <-conn.outgoing:
+		}
+
+		conn.ws.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+		// Send the message to the client; if this times out, the WebSocket
+		// connection will be corrupt, hence we need to close the write loop
+		// and the connection immediately
+		if err := conn.ws.WriteJSON(msg); err != nil {
+			conn.logger.Warnf("sending message failed: %s", err)
+			conn.close()
+			return
+		}
+	}
+}
+
+func (conn *transportConnection) readLoop() {
+	// Close the WebSocket connection when leaving the read loop
+	defer conn.ws.Close()
+	conn.ws.SetReadLimit(conn.config.ReadLimit)
+
+	for {
+		// Read the next message received from the client; if this causes an
+		// error, close the connection and read loop immediately
+		data, err := ReadMessage(conn.ws, conn.config.ReadLimit)
+		if err == ErrMessageTooBig {
+			conn.closeWithCode(websocket.CloseMessageTooBig, "Message too big")
+			return
+		}
+		if err != nil {
+			conn.logger.Warnf("force closing connection: %s", err)
+			conn.close()
+			return
+		}
+
+		msg := transportMessage{}
+		if err := json.Unmarshal(data, &msg); err != nil || msg.Type == "" {
+			conn.closeWithCode(CloseInvalidMessage, "Invalid message received")
+			return
+		}
+
+		conn.mx.Lock()
+		conn.lastActivity = time.Now()
+		conn.mx.Unlock()
+
+		switch msg.Type {
+		// When the connection is initiated, authenticate and send an ACK back
+		case gtwsConnectionInit:
+			conn.mx.Lock()
+			initReceived := conn.initReceived
+			conn.initReceived = true
+			conn.mx.Unlock()
+			if initReceived {
+				conn.closeWithCode(CloseTooManyInitRequests, "Too many initialisation requests")
+				return
+			}
+
+			payload := map[string]interface{}{}
+			if len(msg.Payload) > 0 {
+				if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+					conn.closeWithCode(CloseInvalidMessage, "Invalid connection_init payload")
+					return
+				}
+			}
+
+			if conn.config.Authenticate != nil {
+				ctx, err := conn.config.Authenticate(payload, conn)
+				if err != nil {
+					conn.logger.Debugf("failed to authenticate user: %v", err)
+					conn.closeWithCode(CloseForbidden, "Forbidden")
+					return
+				}
+				conn.mx.Lock()
+				conn.context = authenticatedContext(ctx)
+				conn.mx.Unlock()
+			}
+
+			conn.mx.Lock()
+			conn.acknowledged = true
+			conn.mx.Unlock()
+			conn.send(transportOutgoingMessage{Type: gtwsConnectionAck})
+
+			// ping the client to keep the connection alive
+			if conn.config.KeepAlive > 0 {
+				go every(conn.config.KeepAlive, conn.done, func() {
+					conn.send(transportOutgoingMessage{Type: gtwsPing})
+				})
+			}
+
+		case gtwsPing:
+			pong := transportOutgoingMessage{Type: gtwsPong}
+			if len(msg.Payload) > 0 {
+				pong.Payload = msg.Payload
+			}
+			conn.send(pong)
+
+		case gtwsPong:
+
+		// Let event handlers deal with starting operations
+		case gtwsSubscribe:
+			conn.mx.Lock()
+			acknowledged := conn.acknowledged
+			conn.mx.Unlock()
+			if !acknowledged {
+				conn.closeWithCode(CloseUnauthorized, "Unauthorized")
+				return
+			}
+
+			data := StartMessagePayload{}
+			if msg.ID == "" || json.Unmarshal(msg.Payload, &data) != nil {
+				conn.closeWithCode(CloseInvalidMessage, "Invalid subscribe message")
+				return
+			}
+
+			if code, ok := conn.addOperation(msg.ID); !ok {
+				reason := closeReasonTooManyOps
+				if code == CloseSubscriberAlreadyExist {
+					reason = fmt.Sprintf("Subscriber for %s already exists", msg.ID)
+				}
+				conn.closeWithCode(code, reason)
+				return
+			}
+
+			if conn.config.EventHandlers.StartOperation != nil {
+				if errs := conn.config.EventHandlers.StartOperation(conn, msg.ID, &data); errs != nil {
+					conn.sendOperationErrors(msg.ID, errs)
+				}
+			}
+
+		// Let event handlers deal with stopping operations
+		case gtwsComplete:
+			if conn.removeOperation(msg.ID) && conn.config.EventHandlers.StopOperation != nil {
+				conn.config.EventHandlers.StopOperation(conn, msg.ID)
+			}
+
+		default:
+			conn.closeWithCode(CloseInvalidMessage, fmt.Sprintf("Invalid message type %q", msg.Type))
+			return
+		}
+	}
+}
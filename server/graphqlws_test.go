@@ -0,0 +1,269 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bhoriuchi/graphql-go-tools/server/graphqlws"
+	"github.com/gorilla/websocket"
+)
+
+// dials a websocket test server with the subprotocol
+func dialWS(server *httptest.Server, subprotocol string, header http.Header) (*websocket.Conn, *http.Response, error) {
+	dialer := websocket.Dialer{Subprotocols: []string{subprotocol}, EnableCompression: true}
+	return dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), header)
+}
+
+// writes a message and fails the test on error
+func writeWS(t *testing.T, ws *websocket.Conn, msg string) {
+	t.Helper()
+	if err := ws.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// reads the next message and compares it to the expected JSON
+func expectWS(t *testing.T, ws *websocket.Conn, expected string) {
+	t.Helper()
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	_, msg, err := ws.ReadMessage()
+	if err != nil {
+		t.Fatalf("expected message %s, got error %v", expected, err)
+	}
+
+	var actual, wanted interface{}
+	json.Unmarshal(msg, &actual)
+	json.Unmarshal([]byte(expected), &wanted)
+	a, _ := json.Marshal(actual)
+	w, _ := json.Marshal(wanted)
+	if string(a) != string(w) {
+		t.Errorf("expected message %s, got %s", w, a)
+	}
+}
+
+func TestGraphQLTransportWS(t *testing.T) {
+	server := newTestServer(t, &Options{})
+	defer server.Close()
+
+	ws, _, err := dialWS(server, graphqlws.SubprotocolGraphQLTransportWS, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	if ws.Subprotocol() != graphqlws.SubprotocolGraphQLTransportWS {
+		t.Fatalf("expected subprotocol %s, got %s", graphqlws.SubprotocolGraphQLTransportWS, ws.Subprotocol())
+	}
+
+	writeWS(t, ws, `{"type":"connection_init"}`)
+	expectWS(t, ws, `{"type":"connection_ack"}`)
+
+	writeWS(t, ws, `{"type":"ping","payload":{"n":1}}`)
+	expectWS(t, ws, `{"type":"pong","payload":{"n":1}}`)
+
+	// subscriptions send each result and complete
+	writeWS(t, ws, `{"id":"1","type":"subscribe","payload":{"query":"subscription { count(to: 2) }"}}`)
+	expectWS(t, ws, `{"id":"1","type":"next","payload":{"data":{"count":1}}}`)
+	expectWS(t, ws, `{"id":"1","type":"next","payload":{"data":{"count":2}}}`)
+	expectWS(t, ws, `{"id":"1","type":"complete"}`)
+
+	// queries send a single result
+	writeWS(t, ws, `{"id":"2","type":"subscribe","payload":{"query":"{ hello }"}}`)
+	expectWS(t, ws, `{"id":"2","type":"next","payload":{"data":{"hello":"world"}}}`)
+	expectWS(t, ws, `{"id":"2","type":"complete"}`)
+
+	// mutations send a single result
+	writeWS(t, ws, `{"id":"3","type":"subscribe","payload":{"query":"mutation { echo(text: \"hi\") }"}}`)
+	expectWS(t, ws, `{"id":"3","type":"next","payload":{"data":{"echo":"hi"}}}`)
+	expectWS(t, ws, `{"id":"3","type":"complete"}`)
+}
+
+func TestGraphQLTransportWSProtocolErrors(t *testing.T) {
+	server := newTestServer(t, &Options{})
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		messages []string
+		code     int
+	}{
+		{
+			name:     "subscribe before init",
+			messages: []string{`{"id":"1","type":"subscribe","payload":{"query":"{ hello }"}}`},
+			code:     graphqlws.CloseUnauthorized,
+		},
+		{
+			name:     "too many init requests",
+			messages: []string{`{"type":"connection_init"}`, `{"type":"connection_init"}`},
+			code:     graphqlws.CloseTooManyInitRequests,
+		},
+		{
+			name:     "invalid message type",
+			messages: []string{`{"type":"connection_init"}`, `{"type":"start"}`},
+			code:     graphqlws.CloseInvalidMessage,
+		},
+	}
+
+	for _, test := range tests {
+		ws, _, err := dialWS(server, graphqlws.SubprotocolGraphQLTransportWS, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, msg := range test.messages {
+			writeWS(t, ws, msg)
+		}
+		if code := readWSCloseCode(ws); code != test.code {
+			t.Errorf("%s: expected close code %d, got %d", test.name, test.code, code)
+		}
+		ws.Close()
+	}
+}
+
+func TestGraphQLWS(t *testing.T) {
+	server := newTestServer(t, &Options{})
+	defer server.Close()
+
+	ws, _, err := dialWS(server, graphqlws.SubprotocolGraphQLWS, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	writeWS(t, ws, `{"type":"connection_init","payload":{}}`)
+	expectWS(t, ws, `{"id":"","type":"connection_ack","payload":null}`)
+
+	writeWS(t, ws, `{"id":"1","type":"start","payload":{"query":"subscription { count(to: 2) }"}}`)
+	expectWS(t, ws, `{"id":"1","type":"data","payload":{"data":{"count":1},"errors":[]}}`)
+	expectWS(t, ws, `{"id":"1","type":"data","payload":{"data":{"count":2},"errors":[]}}`)
+	expectWS(t, ws, `{"id":"1","type":"complete","payload":null}`)
+
+	writeWS(t, ws, `{"id":"2","type":"start","payload":{"query":"{ hello }"}}`)
+	expectWS(t, ws, `{"id":"2","type":"data","payload":{"data":{"hello":"world"},"errors":[]}}`)
+	expectWS(t, ws, `{"id":"2","type":"complete","payload":null}`)
+}
+
+// reads messages until the connection is closed and returns the close code
+func readWSCloseCode(ws *websocket.Conn) int {
+	ws.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		if _, _, err := ws.ReadMessage(); err != nil {
+			if e, ok := err.(*websocket.CloseError); ok {
+				return e.Code
+			}
+			return 0
+		}
+	}
+}
+
+func TestWSOrigins(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *WSOptions
+		origin  string
+		allowed bool
+	}{
+		{name: "no options", options: nil, origin: "https://evil.com", allowed: true},
+		{name: "allowed origin", options: &WSOptions{AllowedOrigins: []string{"https://example.com"}}, origin: "https://example.com", allowed: true},
+		{name: "disallowed origin", options: &WSOptions{AllowedOrigins: []string{"https://example.com"}}, origin: "https://evil.com", allowed: false},
+		{name: "no origin", options: &WSOptions{AllowedOrigins: []string{"https://example.com"}}, origin: "", allowed: true},
+		{
+			name: "check origin",
+			options: &WSOptions{
+				AllowedOrigins: []string{"https://example.com"},
+				CheckOrigin:    func(r *http.Request) bool { return false },
+			},
+			origin:  "https://example.com",
+			allowed: false,
+		},
+	}
+
+	for _, test := range tests {
+		server := newTestServer(t, &Options{WS: test.options})
+
+		header := http.Header{}
+		if test.origin != "" {
+			header.Set("Origin", test.origin)
+		}
+		ws, res, err := dialWS(server, graphqlws.SubprotocolGraphQLTransportWS, header)
+		if test.allowed && err != nil {
+			t.Errorf("%s: expected the origin to be allowed, got %v", test.name, err)
+		}
+		if !test.allowed && (err == nil || res == nil || res.StatusCode != http.StatusForbidden) {
+			t.Errorf("%s: expected the origin to be forbidden", test.name)
+		}
+		if ws != nil {
+			ws.Close()
+		}
+		server.Close()
+	}
+}
+
+func TestWSReadLimitAndCompression(t *testing.T) {
+	server := newTestServer(t, &Options{
+		WS: &WSOptions{ReadLimit: 128, EnableCompression: true},
+	})
+	defer server.Close()
+
+	ws, res, err := dialWS(server, graphqlws.SubprotocolGraphQLTransportWS, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	if extensions := res.Header.Get("Sec-Websocket-Extensions"); !strings.Contains(extensions, "permessage-deflate") {
+		t.Errorf("expected compression to be negotiated, got %q", extensions)
+	}
+
+	writeWS(t, ws, `{"type":"connection_init"}`)
+	expectWS(t, ws, `{"type":"connection_ack"}`)
+
+	// messages larger than the read limit close the connection
+	writeWS(t, ws, `{"id":"1","type":"subscribe","payload":{"query":"{ echo(text: \"`+strings.Repeat("a", 256)+`\") }"}}`)
+	if code := readWSCloseCode(ws); code != websocket.CloseMessageTooBig {
+		t.Errorf("expected close code %d, got %d", websocket.CloseMessageTooBig, code)
+	}
+}
+
+func TestGraphQLTransportWSConcurrentOperations(t *testing.T) {
+	s := New(testSchema(t), &Options{})
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	ws, _, err := dialWS(server, graphqlws.SubprotocolGraphQLTransportWS, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+
+	writeWS(t, ws, `{"type":"connection_init"}`)
+	expectWS(t, ws, `{"type":"connection_ack"}`)
+
+	// the connection keeps reading messages while a query executes
+	start := time.Now()
+	writeWS(t, ws, `{"id":"1","type":"subscribe","payload":{"query":"{ wait(ms: 5000) }"}}`)
+	writeWS(t, ws, `{"type":"ping"}`)
+	expectWS(t, ws, `{"type":"pong"}`)
+	writeWS(t, ws, `{"id":"2","type":"subscribe","payload":{"query":"{ hello }"}}`)
+	expectWS(t, ws, `{"id":"2","type":"next","payload":{"data":{"hello":"world"}}}`)
+	expectWS(t, ws, `{"id":"2","type":"complete"}`)
+
+	// completing the slow query cancels it
+	writeWS(t, ws, `{"id":"1","type":"complete"}`)
+	writeWS(t, ws, `{"type":"ping"}`)
+	expectWS(t, ws, `{"type":"pong"}`)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("expected the query to run concurrently, took %s", elapsed)
+	}
+
+	// completed operations are removed from the manager
+	s.mgr.mx.Lock()
+	conns := len(s.mgr.conns)
+	s.mgr.mx.Unlock()
+	if conns != 0 {
+		t.Errorf("expected no active operations, got %d connections", conns)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/bhoriuchi/graphql-go-tools/server/graphqlws"
 	"github.com/graphql-go/graphql"
 	"github.com/graphql-go/graphql/gqlerrors"
 )
@@ -18,6 +19,8 @@ type RequestOptions struct {
 	Query         string                 `json:"query" url:"query" schema:"query"`
 	Variables     map[string]interface{} `json:"variables" url:"variables" schema:"variables"`
 	OperationName string                 `json:"operationName" url:"operationName" schema:"operationName"`
+	Extensions    map[string]interface{} `json:"extensions" url:"extensions" schema:"extensions"`
+	ID            string                 `json:"id" url:"id" schema:"id"`
 }
 
 // a workaround for getting`variables` as a JSON string
@@ -29,22 +32,41 @@ type requestOptionsCompatibility struct {
 
 func getFromForm(values url.Values) *RequestOptions {
 	query := values.Get("query")
-	if query != "" {
+	if query != "" || values.Get("extensions") != "" || values.Get("id") != "" {
 		// get variables map
 		variables := make(map[string]interface{}, len(values))
 		variablesStr := values.Get("variables")
 		json.Unmarshal([]byte(variablesStr), &variables)
 
+		// get extensions map
+		extensions := map[string]interface{}{}
+		json.Unmarshal([]byte(values.Get("extensions")), &extensions)
+
 		return &RequestOptions{
 			Query:         query,
 			Variables:     variables,
 			OperationName: values.Get("operationName"),
+			Extensions:    extensions,
+			ID:            values.Get("id"),
 		}
 	}
 
 	return nil
 }
 
+// parses a JSON body into request options
+func unmarshalRequestOptions(body []byte) *RequestOptions {
+	var opts RequestOptions
+	if err := json.Unmarshal(body, &opts); err != nil {
+		// Probably `variables` was sent as a string instead of an object.
+		// So, we try to be polite and try to parse that as a JSON string
+		var optsCompatible requestOptionsCompatibility
+		json.Unmarshal(body, &optsCompatible)
+		json.Unmarshal([]byte(optsCompatible.Variables), &opts.Variables)
+	}
+	return &opts
+}
+
 // NewRequestOptions Parses a http.Request into GraphQL request options struct
 func NewRequestOptions(r *http.Request) *RequestOptions {
 	if reqOpt := getFromForm(r.URL.Query()); reqOpt != nil {
@@ -87,20 +109,11 @@ func NewRequestOptions(r *http.Request) *RequestOptions {
 	case ContentTypeJSON:
 		fallthrough
 	default:
-		var opts RequestOptions
 		body, err := ioutil.ReadAll(r.Body)
 		if err != nil {
-			return &opts
-		}
-		err = json.Unmarshal(body, &opts)
-		if err != nil {
-			// Probably `variables` was sent as a string instead of an object.
-			// So, we try to be polite and try to parse that as a JSON string
-			var optsCompatible requestOptionsCompatibility
-			json.Unmarshal(body, &optsCompatible)
-			json.Unmarshal([]byte(optsCompatible.Variables), &opts.Variables)
+			return &RequestOptions{}
 		}
-		return &opts
+		return unmarshalRequestOptions(body)
 	}
 }
 
@@ -147,51 +160,44 @@ func GetRequestOptions(r *http.Request) *RequestOptions {
 	case ContentTypeJSON:
 		fallthrough
 	default:
-		var opts RequestOptions
 		body, err := ioutil.ReadAll(r.Body)
 		if err != nil {
-			return &opts
+			return &RequestOptions{}
 		}
 		r.Body = ioutil.NopCloser(bytes.NewBuffer(body))
-		err = json.Unmarshal(body, &opts)
-		if err != nil {
-			// Probably `variables` was sent as a string instead of an object.
-			// So, we try to be polite and try to parse that as a JSON string
-			var optsCompatible requestOptionsCompatibility
-			json.Unmarshal(body, &optsCompatible)
-			json.Unmarshal([]byte(optsCompatible.Variables), &opts.Variables)
-		}
-		return &opts
+		return unmarshalRequestOptions(body)
 	}
 }
 
 // ContextHandler provides an entrypoint into executing graphQL queries with a
 // user-provided context.
 func (s *Server) ContextHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
-	// get query
-	opts := NewRequestOptions(r)
-
-	// execute graphql query
-	params := graphql.Params{
-		Schema:         s.schema,
-		RequestString:  opts.Query,
-		VariableValues: opts.Variables,
-		OperationName:  opts.OperationName,
-		Context:        ctx,
+	// execute a multipart request with file uploads
+	if s.options.Upload != nil && IsMultipartRequest(r) {
+		s.uploadHandler(ctx, w, r)
+		return
 	}
-	if s.options.RootValueFunc != nil {
-		params.RootObject = s.options.RootValueFunc(ctx, r)
+
+	// execute a request following the GraphQL over HTTP spec
+	if s.options.SpecCompliant && !s.rendersIDE(r) {
+		s.specHandler(ctx, w, r)
+		return
 	}
-	result := graphql.Do(params)
 
-	if formatErrorFunc := s.options.FormatErrorFunc; formatErrorFunc != nil && len(result.Errors) > 0 {
-		formatted := make([]gqlerrors.FormattedError, len(result.Errors))
-		for i, formattedError := range result.Errors {
-			formatted[i] = formatErrorFunc(formattedError.OriginalError())
+	// execute a batch of queries
+	if s.options.Batch != nil {
+		if batch, ok := NewBatchRequestOptions(r); ok {
+			s.batchHandler(ctx, w, r, ContentTypeJSON, batch, s.execute)
+			return
 		}
-		result.Errors = formatted
 	}
 
+	// get query
+	opts := NewRequestOptions(r)
+
+	// execute graphql query
+	params, result := s.execute(ctx, r, opts)
+
 	if s.options.GraphiQL != nil {
 		acceptHeader := r.Header.Get("Accept")
 		_, raw := r.URL.Query()["raw"]
@@ -208,25 +214,64 @@ func (s *Server) ContextHandler(ctx context.Context, w http.ResponseWriter, r *h
 		}
 	}
 
+	buff := s.writeJSON(w, http.StatusOK, result)
+
+	if s.options.ResultCallbackFunc != nil {
+		s.options.ResultCallbackFunc(ctx, &params, result, buff)
+	}
+}
+
+// executes a graphql query, rejecting queries that exceed the limits
+func (s *Server) execute(ctx context.Context, r *http.Request, opts *RequestOptions) (graphql.Params, *graphql.Result) {
+	if err := s.resolveQuery(ctx, opts); err != nil {
+		return graphql.Params{Schema: s.schema, Context: ctx}, s.formatResult(persistedQueryResult(err))
+	}
+	return s.run(ctx, r, opts)
+}
+
+// runs a graphql query that has already been resolved
+func (s *Server) run(ctx context.Context, r *http.Request, opts *RequestOptions) (graphql.Params, *graphql.Result) {
+	params := graphql.Params{
+		Schema:         s.schema,
+		RequestString:  opts.Query,
+		VariableValues: opts.Variables,
+		OperationName:  opts.OperationName,
+		Context:        ctx,
+	}
+	if s.options.RootValueFunc != nil {
+		params.RootObject = s.options.RootValueFunc(ctx, r)
+	}
+
+	var result *graphql.Result
+	if errs := s.options.Limits.Validate(s.schema, opts.Query, opts.OperationName, opts.Variables); errs != nil {
+		result = &graphql.Result{Errors: errs}
+	} else {
+		result = graphql.Do(params)
+	}
+
+	return params, s.formatResult(result)
+}
+
+// writes a JSON response and returns the response body
+func (s *Server) writeJSON(w http.ResponseWriter, status int, v interface{}) []byte {
+	return s.writeResponse(w, status, ContentTypeJSON, v)
+}
+
+// writes a JSON encoded response with the media type and returns the response body
+func (s *Server) writeResponse(w http.ResponseWriter, status int, mediaType string, v interface{}) []byte {
 	// use proper JSON Header
-	w.Header().Add("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("Content-Type", mediaType+"; charset=utf-8")
 
 	var buff []byte
 	if s.options.Pretty {
-		w.WriteHeader(http.StatusOK)
-		buff, _ = json.MarshalIndent(result, "", "\t")
-
-		w.Write(buff)
+		buff, _ = json.MarshalIndent(v, "", "\t")
 	} else {
-		w.WriteHeader(http.StatusOK)
-		buff, _ = json.Marshal(result)
-
-		w.Write(buff)
+		buff, _ = json.Marshal(v)
 	}
 
-	if s.options.ResultCallbackFunc != nil {
-		s.options.ResultCallbackFunc(ctx, &params, result, buff)
-	}
+	w.WriteHeader(status)
+	w.Write(buff)
+	return buff
 }
 
 func (s *Server) WSHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
@@ -239,13 +284,25 @@ func (s *Server) WSHandler(ctx context.Context, w http.ResponseWriter, r *http.R
 		return
 	}
 
-	// Close the connection early if it doesn't implement the graphql-ws protocol
-	if ws.Subprotocol() == "graphql-ws" {
+	// Close the connection early if it doesn't implement a supported protocol
+	switch ws.Subprotocol() {
+	case graphqlws.SubprotocolGraphQLWS, graphqlws.SubprotocolGraphQLTransportWS:
 		s.newGraphQLWSConnection(ctx, r, ws)
 		return
 	}
 
-	// TODO: support other popular protocols
-	s.log.Warnf("Connection does not implement the GraphQL WS protocol. Subprotocol: %s", ws.Subprotocol())
+	s.log.Warnf("Connection does not implement a GraphQL WS protocol. Subprotocol: %s", ws.Subprotocol())
 	ws.Close()
 }
+
+// formats the errors of a result with the FormatErrorFunc
+func (s *Server) formatResult(result *graphql.Result) *graphql.Result {
+	if formatErrorFunc := s.options.FormatErrorFunc; formatErrorFunc != nil && len(result.Errors) > 0 {
+		formatted := make([]gqlerrors.FormattedError, len(result.Errors))
+		for i, formattedError := range result.Errors {
+			formatted[i] = formatErrorFunc(formattedError.OriginalError())
+		}
+		result.Errors = formatted
+	}
+	return result
+}
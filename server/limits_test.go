@@ -0,0 +1,31 @@
+package server
+
+import (
+	"strings"
+	"testing"
+
+	tools "github.com/bhoriuchi/graphql-go-tools"
+)
+
+func TestLimits(t *testing.T) {
+	server := newTestServer(t, &Options{
+		Limits: &tools.QueryLimits{MaxAliases: 1, MaxComplexity: 2},
+	})
+	defer server.Close()
+
+	tests := []struct {
+		query    string
+		contains string
+	}{
+		{query: `{ hello a: echo(text: "a") }`, contains: `{"data":{"a":"a","hello":"world"}}`},
+		{query: `{ a: hello b: hello }`, contains: "aliases"},
+		{query: `{ hello echo(text: "a") hi: hello }`, contains: "complexity"},
+	}
+
+	for _, test := range tests {
+		_, body := postJSON(t, server.URL, map[string]interface{}{"query": test.query})
+		if !strings.Contains(string(body), test.contains) {
+			t.Errorf("%s: expected response containing %q, got %s", test.query, test.contains, body)
+		}
+	}
+}
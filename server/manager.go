@@ -33,6 +33,24 @@ func (c *ChanMgr) Add(rc *ResultChan) { // Add(cid, oid string, ch chan *graphql
 	conn[rc.oid] = rc
 }
 
+// adds a result channel unless the connection already has an operation with its id
+func (c *ChanMgr) addIfAbsent(rc *ResultChan) bool {
+	c.mx.Lock()
+	defer c.mx.Unlock()
+
+	conn, ok := c.conns[rc.cid]
+	if !ok {
+		conn = make(map[string]*ResultChan)
+		c.conns[rc.cid] = conn
+	}
+
+	if _, exists := conn[rc.oid]; exists {
+		return false
+	}
+	conn[rc.oid] = rc
+	return true
+}
+
 func (c *ChanMgr) DelConn(cid string) bool {
 	c.mx.Lock()
 	defer c.mx.Unlock()
@@ -49,7 +67,7 @@ func (c *ChanMgr) DelConn(cid string) bool {
 		delete(conn, oid)
 	}
 
-	delete(conn, cid)
+	delete(c.conns, cid)
 	return true
 }
 
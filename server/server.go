@@ -4,7 +4,10 @@ import (
 	"context"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
 
+	tools "github.com/bhoriuchi/graphql-go-tools"
 	"github.com/bhoriuchi/graphql-go-tools/server/graphqlws"
 	"github.com/bhoriuchi/graphql-go-tools/server/logger"
 	"github.com/gorilla/websocket"
@@ -28,6 +31,9 @@ type Server struct {
 	options  *Options
 	upgrader websocket.Upgrader
 	mgr      *ChanMgr
+
+	streamsMx sync.Mutex
+	streams   map[string]*sseStream
 }
 
 func New(schema graphql.Schema, options *Options) *Server {
@@ -35,17 +41,19 @@ func New(schema graphql.Schema, options *Options) *Server {
 		options.Logger = &logger.NoopLogger{}
 	}
 
+	if options.PersistedQueries != nil && options.PersistedQueries.Store == nil {
+		options.PersistedQueries.Store = NewLRUPersistedQueryStore(defaultPersistedQueryCacheLen)
+	}
+
 	return &Server{
-		schema:  schema,
-		log:     options.Logger,
-		options: options,
-		upgrader: websocket.Upgrader{
-			CheckOrigin:  func(r *http.Request) bool { return true },
-			Subprotocols: []string{"graphql-ws"},
-		},
+		schema:   schema,
+		log:      options.Logger,
+		options:  options,
+		upgrader: newUpgrader(options.WS),
 		mgr: &ChanMgr{
 			conns: make(map[string]map[string]*ResultChan),
 		},
+		streams: make(map[string]*sseStream),
 	}
 }
 
@@ -59,19 +67,40 @@ type ResultCallbackFunc func(ctx context.Context, params *graphql.Params, result
 
 type Options struct {
 	Pretty             bool
+	SpecCompliant      bool // follow the GraphQL over HTTP spec for status codes and content types
 	RootValueFunc      RootValueFunc
 	FormatErrorFunc    FormatErrorFunc
 	ContextFunc        ContextFunc
 	WSContextFunc      ContextFunc
 	ResultCallbackFunc ResultCallbackFunc
 	Logger             logger.Logger
+	Limits             *tools.QueryLimits
 	WS                 *WSOptions
+	SSE                *SSEOptions
+	Batch              *BatchOptions
+	PersistedQueries   *PersistedQueryOptions
+	AllowList          *AllowListOptions
+	Upload             *UploadOptions
 	Playground         *PlaygroundOptions
 	GraphiQL           *GraphiQLOptions
 }
 
 type WSOptions struct {
 	AuthenticateFunc graphqlws.AuthenticateFunc
+	InitTimeout      time.Duration // time allowed for clients to send connection_init
+	KeepAlive        time.Duration // interval of keep-alive messages, disabled when 0
+	IdleTimeout      time.Duration // closes connections without operations or messages for the duration
+	MaxOperations    int           // maximum active operations per connection, unlimited when 0
+	ReadLimit        int64         // maximum size in bytes of incoming messages, defaults to 4096
+
+	// CheckOrigin validates the Origin header of upgrade requests. When not set
+	// the origin must be in AllowedOrigins, all origins are allowed when both are empty
+	CheckOrigin    func(r *http.Request) bool
+	AllowedOrigins []string // allowed origins such as https://example.com, * allows all
+
+	EnableCompression bool // negotiates permessage-deflate compression with clients
+	ReadBufferSize    int  // size of the read buffer in bytes, defaults to 4096
+	WriteBufferSize   int  // size of the write buffer in bytes, defaults to 4096
 }
 
 func IsWSUpgrade(r *http.Request) bool {
@@ -89,6 +118,12 @@ func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			ctx = s.options.WSContextFunc(r)
 		}
 		s.WSHandler(ctx, w, r)
+	} else if s.options.SSE != nil && IsSSERequest(r) {
+		ctx := r.Context()
+		if s.options.ContextFunc != nil {
+			ctx = s.options.ContextFunc(r)
+		}
+		s.SSEHandler(ctx, w, r)
 	} else {
 		ctx := r.Context()
 		if s.options.ContextFunc != nil {
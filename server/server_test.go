@@ -0,0 +1,187 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bhoriuchi/graphql-go-tools/scalars"
+	"github.com/graphql-go/graphql"
+)
+
+// creates a schema with queries, a mutation, uploads and a counting subscription.
+// The wait query resolves after a delay or once its context is done
+func testSchema(t *testing.T) graphql.Schema {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"hello": &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return "world", nil
+					},
+				},
+				"echo": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"text": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Args["text"], nil
+					},
+				},
+				"wait": &graphql.Field{
+					Type: graphql.Boolean,
+					Args: graphql.FieldConfigArgument{
+						"ms": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						ms, _ := p.Args["ms"].(int)
+						select {
+						case <-time.After(time.Duration(ms) * time.Millisecond):
+							return true, nil
+						case <-p.Context.Done():
+							return false, nil
+						}
+					},
+				},
+				"upload": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"file": &graphql.ArgumentConfig{Type: scalars.ScalarUpload},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						upload, ok := p.Args["file"].(*scalars.Upload)
+						if !ok {
+							return nil, nil
+						}
+						content, err := ioutil.ReadAll(upload.File)
+						if err != nil {
+							return nil, err
+						}
+						return upload.Filename + ":" + string(content), nil
+					},
+				},
+			},
+		}),
+		Mutation: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Mutation",
+			Fields: graphql.Fields{
+				"echo": &graphql.Field{
+					Type: graphql.String,
+					Args: graphql.FieldConfigArgument{
+						"text": &graphql.ArgumentConfig{Type: graphql.String},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Args["text"], nil
+					},
+				},
+			},
+		}),
+		Subscription: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Subscription",
+			Fields: graphql.Fields{
+				"count": &graphql.Field{
+					Type: graphql.Int,
+					Args: graphql.FieldConfigArgument{
+						"to": &graphql.ArgumentConfig{Type: graphql.Int},
+					},
+					Subscribe: func(p graphql.ResolveParams) (interface{}, error) {
+						to, _ := p.Args["to"].(int)
+						ch := make(chan interface{})
+						go func() {
+							defer close(ch)
+							for i := 1; i <= to; i++ {
+								select {
+								case ch <- i:
+								case <-p.Context.Done():
+									return
+								}
+							}
+						}()
+						return ch, nil
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Source, nil
+					},
+				},
+			},
+		}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return schema
+}
+
+// starts a test server serving the test schema
+func newTestServer(t *testing.T, options *Options) *httptest.Server {
+	return httptest.NewServer(New(testSchema(t), options))
+}
+
+// sends a request and returns the response with its body
+func doRequest(t *testing.T, r *http.Request) (*http.Response, []byte) {
+	client := http.Client{Timeout: 5 * time.Second}
+	res, err := client.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res, body
+}
+
+// posts a JSON value and returns the response with its body
+func postJSON(t *testing.T, url string, v interface{}) (*http.Response, []byte) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", ContentTypeJSON)
+	return doRequest(t, r)
+}
+
+// asserts the JSON response body equals the expected JSON
+func assertJSON(t *testing.T, body []byte, expected string) {
+	t.Helper()
+	var actual, wanted interface{}
+	if err := json.Unmarshal(body, &actual); err != nil {
+		t.Fatalf("invalid JSON response %q: %v", body, err)
+	}
+	if err := json.Unmarshal([]byte(expected), &wanted); err != nil {
+		t.Fatal(err)
+	}
+	a, _ := json.Marshal(actual)
+	w, _ := json.Marshal(wanted)
+	if !bytes.Equal(a, w) {
+		t.Errorf("expected %s, got %s", w, a)
+	}
+}
+
+func TestServerQuery(t *testing.T) {
+	server := newTestServer(t, &Options{})
+	defer server.Close()
+
+	_, body := postJSON(t, server.URL, map[string]interface{}{
+		"query":     `query ($text: String) { hello echo(text: $text) }`,
+		"variables": map[string]interface{}{"text": "hi"},
+	})
+	assertJSON(t, body, `{"data":{"hello":"world","echo":"hi"}}`)
+
+	r, _ := http.NewRequest(http.MethodGet, server.URL+"?query={hello}", nil)
+	_, body = doRequest(t, r)
+	assertJSON(t, body, `{"data":{"hello":"world"}}`)
+}
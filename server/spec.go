@@ -0,0 +1,266 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/bhoriuchi/graphql-go-tools/internal/batch"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// ContentTypeGraphQLResponseJSON the media type of GraphQL over HTTP responses
+const ContentTypeGraphQLResponseJSON = "application/graphql-response+json"
+
+// specError an error with the http status it should be returned with
+type specError struct {
+	status int
+	err    error
+}
+
+func (e *specError) Error() string {
+	return e.err.Error()
+}
+
+// determines if a request will render GraphiQL or Playground
+func (s *Server) rendersIDE(r *http.Request) bool {
+	if s.options.GraphiQL == nil && s.options.Playground == nil {
+		return false
+	}
+	acceptHeader := r.Header.Get("Accept")
+	_, raw := r.URL.Query()["raw"]
+	return r.Method == http.MethodGet && !raw &&
+		!strings.Contains(acceptHeader, "application/json") &&
+		strings.Contains(acceptHeader, "text/html")
+}
+
+// specHandler handles a request following the GraphQL over HTTP spec. Malformed
+// requests are rejected with a 4xx status and requests that fail to parse or
+// validate return 400 when the client accepts application/graphql-response+json.
+// Batches are executed with the same rules and return an array of results
+func (s *Server) specHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodPost {
+		w.Header().Set("Allow", "GET, POST")
+		s.writeJSON(w, http.StatusMethodNotAllowed, errorResult(fmt.Errorf("method %s is not allowed", r.Method)))
+		return
+	}
+
+	mediaType, ok := negotiateMediaType(r.Header.Get("Accept"))
+	if !ok {
+		s.writeJSON(w, http.StatusNotAcceptable, errorResult(fmt.Errorf("the accepted media types are not supported")))
+		return
+	}
+
+	// request errors use 200 for application/json responses for compatibility
+	requestErrorStatus := http.StatusOK
+	if mediaType == ContentTypeGraphQLResponseJSON {
+		requestErrorStatus = http.StatusBadRequest
+	}
+
+	batch, isBatch, err := parseSpecRequestOptions(r, s.options.Batch != nil)
+	if err != nil {
+		status := http.StatusBadRequest
+		if e, ok := err.(*specError); ok {
+			status = e.status
+		}
+		s.writeResponse(w, status, mediaType, errorResult(err))
+		return
+	}
+
+	if isBatch {
+		s.batchHandler(ctx, w, r, mediaType, batch, func(ctx context.Context, r *http.Request, opts *RequestOptions) (graphql.Params, *graphql.Result) {
+			params, result, _ := s.specExecute(ctx, r, opts, requestErrorStatus)
+			return params, result
+		})
+		return
+	}
+
+	params, result, status := s.specExecute(ctx, r, batch[0], requestErrorStatus)
+	if status == http.StatusMethodNotAllowed {
+		w.Header().Set("Allow", "POST")
+		s.writeResponse(w, status, mediaType, result)
+		return
+	}
+	buff := s.writeResponse(w, status, mediaType, result)
+
+	if s.options.ResultCallbackFunc != nil {
+		s.options.ResultCallbackFunc(ctx, &params, result, buff)
+	}
+}
+
+// executes an operation following the GraphQL over HTTP spec and returns the
+// status of its response. Documents that fail to parse or validate return the
+// request error status and operations other than queries cannot use GET
+func (s *Server) specExecute(ctx context.Context, r *http.Request, opts *RequestOptions, requestErrorStatus int) (graphql.Params, *graphql.Result, int) {
+	params := graphql.Params{Schema: s.schema, Context: ctx}
+	if err := s.resolveQuery(ctx, opts); err != nil {
+		return params, s.formatResult(persistedQueryResult(err)), requestErrorStatus
+	}
+
+	// parse the document to report syntax errors and find the operation type
+	src := source.NewSource(&source.Source{Body: []byte(opts.Query), Name: "GraphQL request"})
+	document, err := parser.Parse(parser.ParseParams{Source: src})
+	if err != nil {
+		return params, s.formatResult(&graphql.Result{Errors: gqlerrors.FormatErrors(err)}), requestErrorStatus
+	}
+
+	// only queries can be executed with GET
+	if r.Method == http.MethodGet {
+		if op := findOperation(document, opts.OperationName); op != nil && op.Operation != ast.OperationTypeQuery {
+			return params, errorResult(fmt.Errorf("%s operations can only be executed with POST", op.Operation)), http.StatusMethodNotAllowed
+		}
+	}
+
+	// results without data are request errors such as validation failures
+	params, result := s.run(ctx, r, opts)
+	if result.Data == nil && len(result.Errors) > 0 {
+		return params, result, requestErrorStatus
+	}
+	return params, result, http.StatusOK
+}
+
+// parses request options from a GET query string or a POST application/json
+// body, rejecting malformed parameters. When batches are allowed a POST body
+// with a JSON array is parsed into a batch and true is returned
+func parseSpecRequestOptions(r *http.Request, allowBatch bool) ([]*RequestOptions, bool, error) {
+	if r.Method == http.MethodGet {
+		opts := &RequestOptions{}
+		values := r.URL.Query()
+		opts.Query = values.Get("query")
+		opts.OperationName = values.Get("operationName")
+		opts.ID = values.Get("id")
+		if err := unmarshalParam("variables", values.Get("variables"), &opts.Variables); err != nil {
+			return nil, false, err
+		}
+		if err := unmarshalParam("extensions", values.Get("extensions"), &opts.Extensions); err != nil {
+			return nil, false, err
+		}
+		return []*RequestOptions{opts}, false, nil
+	}
+
+	contentType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || contentType != ContentTypeJSON {
+		return nil, false, &specError{
+			status: http.StatusUnsupportedMediaType,
+			err:    fmt.Errorf("unsupported content type %q, requests must use %s", r.Header.Get("Content-Type"), ContentTypeJSON),
+		}
+	}
+
+	if r.Body == nil {
+		return nil, false, errors.New("request body is required")
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read request body: %v", err)
+	}
+
+	if allowBatch {
+		if items, ok := batch.Split(body); ok {
+			opts := make([]*RequestOptions, len(items))
+			for i, item := range items {
+				if opts[i], err = decodeSpecParams(item); err != nil {
+					return nil, false, fmt.Errorf("invalid batch operation %d: %v", i, err)
+				}
+			}
+			return opts, true, nil
+		}
+	}
+
+	opts, err := decodeSpecParams(body)
+	if err != nil {
+		return nil, false, err
+	}
+	return []*RequestOptions{opts}, false, nil
+}
+
+// decodes the parameters of a JSON object individually to reject parameters
+// with the wrong type
+func decodeSpecParams(body []byte) (*RequestOptions, error) {
+	params := map[string]json.RawMessage{}
+	if err := json.Unmarshal(body, &params); err != nil {
+		return nil, fmt.Errorf("request body must be a JSON object: %v", err)
+	}
+
+	opts := &RequestOptions{}
+	fields := map[string]interface{}{
+		"query":         &opts.Query,
+		"operationName": &opts.OperationName,
+		"variables":     &opts.Variables,
+		"extensions":    &opts.Extensions,
+		"id":            &opts.ID,
+	}
+	for name, value := range fields {
+		if raw, ok := params[name]; ok && !bytes.Equal(raw, []byte("null")) {
+			if err := json.Unmarshal(raw, value); err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", name, err)
+			}
+		}
+	}
+
+	return opts, nil
+}
+
+// unmarshals a JSON object query parameter
+func unmarshalParam(name, value string, v interface{}) error {
+	if value == "" {
+		return nil
+	}
+	if err := json.Unmarshal([]byte(value), v); err != nil {
+		return fmt.Errorf("invalid %s: %v", name, err)
+	}
+	return nil
+}
+
+// selects the response media type from an Accept header. Clients that do not
+// send an Accept header receive application/json
+func negotiateMediaType(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return ContentTypeJSON, true
+	}
+
+	acceptsJSON := false
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case ContentTypeGraphQLResponseJSON:
+			return ContentTypeGraphQLResponseJSON, true
+		case ContentTypeJSON, "application/*", "*/*":
+			acceptsJSON = true
+		}
+	}
+
+	if acceptsJSON {
+		return ContentTypeJSON, true
+	}
+	return "", false
+}
+
+// finds the operation to execute in a document
+func findOperation(document *ast.Document, operationName string) *ast.OperationDefinition {
+	for _, def := range document.Definitions {
+		if op, ok := def.(*ast.OperationDefinition); ok {
+			if operationName == "" || (op.Name != nil && op.Name.Value == operationName) {
+				return op
+			}
+		}
+	}
+	return nil
+}
+
+// creates a result with a single error
+func errorResult(err error) *graphql.Result {
+	return &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+}
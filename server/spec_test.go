@@ -0,0 +1,130 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSpecCompliant(t *testing.T) {
+	server := newTestServer(t, &Options{SpecCompliant: true})
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		method      string
+		query       string
+		contentType string
+		accept      string
+		body        string
+		status      int
+		mediaType   string
+		allow       string
+	}{
+		{
+			name:   "query with GET",
+			method: http.MethodGet, query: "query=" + url.QueryEscape(`{ hello }`),
+			status: http.StatusOK, mediaType: ContentTypeJSON,
+		},
+		{
+			name:   "query with POST",
+			method: http.MethodPost, contentType: ContentTypeJSON, accept: ContentTypeGraphQLResponseJSON,
+			body:   `{"query":"{ hello }"}`,
+			status: http.StatusOK, mediaType: ContentTypeGraphQLResponseJSON,
+		},
+		{
+			name:   "unsupported method",
+			method: http.MethodPatch,
+			status: http.StatusMethodNotAllowed, mediaType: ContentTypeJSON, allow: "GET, POST",
+		},
+		{
+			name:   "mutation with GET",
+			method: http.MethodGet, query: "query=" + url.QueryEscape(`mutation { echo(text: "hi") }`),
+			status: http.StatusMethodNotAllowed, mediaType: ContentTypeJSON, allow: "POST",
+		},
+		{
+			name:   "unsupported accept",
+			method: http.MethodGet, query: "query=" + url.QueryEscape(`{ hello }`), accept: "text/html",
+			status: http.StatusNotAcceptable, mediaType: ContentTypeJSON,
+		},
+		{
+			name:   "unsupported content type",
+			method: http.MethodPost, contentType: "text/plain", body: `{ hello }`,
+			status: http.StatusUnsupportedMediaType, mediaType: ContentTypeJSON,
+		},
+		{
+			name:   "malformed body",
+			method: http.MethodPost, contentType: ContentTypeJSON, body: `{"query":`,
+			status: http.StatusBadRequest, mediaType: ContentTypeJSON,
+		},
+		{
+			name:   "invalid parameter type",
+			method: http.MethodPost, contentType: ContentTypeJSON, body: `{"query":"{ hello }","variables":[]}`,
+			status: http.StatusBadRequest, mediaType: ContentTypeJSON,
+		},
+		{
+			// application/json responses use 200 for request errors
+			name:   "syntax error with json",
+			method: http.MethodPost, contentType: ContentTypeJSON, accept: ContentTypeJSON,
+			body:   `{"query":"{ hello"}`,
+			status: http.StatusOK, mediaType: ContentTypeJSON,
+		},
+		{
+			name:   "syntax error",
+			method: http.MethodPost, contentType: ContentTypeJSON, accept: ContentTypeGraphQLResponseJSON,
+			body:   `{"query":"{ hello"}`,
+			status: http.StatusBadRequest, mediaType: ContentTypeGraphQLResponseJSON,
+		},
+		{
+			name:   "validation error",
+			method: http.MethodPost, contentType: ContentTypeJSON, accept: ContentTypeGraphQLResponseJSON,
+			body:   `{"query":"{ unknown }"}`,
+			status: http.StatusBadRequest, mediaType: ContentTypeGraphQLResponseJSON,
+		},
+	}
+
+	for _, test := range tests {
+		r, err := http.NewRequest(test.method, server.URL+"?"+test.query, strings.NewReader(test.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if test.contentType != "" {
+			r.Header.Set("Content-Type", test.contentType)
+		}
+		if test.accept != "" {
+			r.Header.Set("Accept", test.accept)
+		}
+
+		res, body := doRequest(t, r)
+		if res.StatusCode != test.status {
+			t.Errorf("%s: expected status %d, got %d %s", test.name, test.status, res.StatusCode, body)
+		}
+		if contentType := res.Header.Get("Content-Type"); !strings.HasPrefix(contentType, test.mediaType) {
+			t.Errorf("%s: expected content type %s, got %s", test.name, test.mediaType, contentType)
+		}
+		if allow := res.Header.Get("Allow"); allow != test.allow {
+			t.Errorf("%s: expected allow %q, got %q", test.name, test.allow, allow)
+		}
+	}
+}
+
+func TestNegotiateMediaType(t *testing.T) {
+	tests := []struct {
+		accept    string
+		mediaType string
+		ok        bool
+	}{
+		{accept: "", mediaType: ContentTypeJSON, ok: true},
+		{accept: "*/*", mediaType: ContentTypeJSON, ok: true},
+		{accept: "application/json, application/graphql-response+json;q=0.9", mediaType: ContentTypeGraphQLResponseJSON, ok: true},
+		{accept: "text/html", ok: false},
+	}
+
+	for _, test := range tests {
+		mediaType, ok := negotiateMediaType(test.accept)
+		if mediaType != test.mediaType || ok != test.ok {
+			t.Errorf("%q: expected %q %t, got %q %t", test.accept, test.mediaType, test.ok, mediaType, ok)
+		}
+	}
+}
@@ -0,0 +1,437 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bhoriuchi/graphql-go-tools/server/graphqlws"
+	"github.com/google/uuid"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// Constants for GraphQL over Server-Sent Events
+const (
+	ContentTypeEventStream = "text/event-stream"
+	SSETokenHeader         = "X-GraphQL-Event-Stream-Token"
+	SSETokenParam          = "token"
+	SSEOperationIDParam    = "operationId"
+
+	sseEventNext                 = "next"
+	sseEventComplete             = "complete"
+	defaultSSEKeepAlive          = 12 * time.Second
+	defaultSSEReservationTimeout = 30 * time.Second
+)
+
+// SSEOptions options for serving operations over Server-Sent Events
+type SSEOptions struct {
+	KeepAlive          time.Duration // interval between keep-alive comments, defaults to 12 seconds
+	ReservationTimeout time.Duration // time a reserved stream is kept before it must be opened, defaults to 30 seconds
+}
+
+// sseStream a stream reserved by a client in single connection mode
+type sseStream struct {
+	mx       sync.Mutex
+	open     bool
+	released bool
+	timer    *time.Timer
+	done     chan struct{}
+	events   chan sseEvent
+}
+
+// sseEvent an event sent to a stream
+type sseEvent struct {
+	event string
+	data  interface{}
+}
+
+// sseStreamMessage the data of an event in single connection mode
+type sseStreamMessage struct {
+	ID      string      `json:"id"`
+	Payload interface{} `json:"payload,omitempty"`
+}
+
+// IsSSERequest determines if a request uses the GraphQL over SSE protocol
+func IsSSERequest(r *http.Request) bool {
+	return r.Method == http.MethodPut ||
+		getSSEToken(r) != "" ||
+		strings.Contains(r.Header.Get("Accept"), ContentTypeEventStream)
+}
+
+// gets the stream token from the header or query
+func getSSEToken(r *http.Request) string {
+	if token := r.Header.Get(SSETokenHeader); token != "" {
+		return token
+	}
+	return r.URL.Query().Get(SSETokenParam)
+}
+
+// SSEHandler serves operations over Server-Sent Events. Requests with a
+// stream token use single connection mode where the stream is reserved with
+// a PUT, opened with a GET, operations are started with a POST and stopped
+// with a DELETE. All other requests use distinct connections mode where the
+// results of the operation are streamed in the response
+func (s *Server) SSEHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPut {
+		s.reserveSSEStream(w)
+		return
+	}
+
+	if token := getSSEToken(r); token != "" {
+		s.handleSSEStream(ctx, w, r, token)
+		return
+	}
+
+	s.serveSSEOperation(ctx, w, r)
+}
+
+// reserves a stream for single connection mode and responds with its token.
+// Streams that are not opened before the reservation timeout are released
+func (s *Server) reserveSSEStream(w http.ResponseWriter) {
+	token := uuid.New().String()
+	stream := &sseStream{
+		done:   make(chan struct{}),
+		events: make(chan sseEvent),
+	}
+
+	stream.mx.Lock()
+	s.streamsMx.Lock()
+	s.streams[token] = stream
+	s.streamsMx.Unlock()
+	stream.timer = time.AfterFunc(s.sseReservationTimeout(), func() {
+		s.releaseUnopenedSSEStream(token, stream)
+	})
+	stream.mx.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(token))
+}
+
+// handles requests for a reserved stream
+func (s *Server) handleSSEStream(ctx context.Context, w http.ResponseWriter, r *http.Request, token string) {
+	s.streamsMx.Lock()
+	stream, ok := s.streams[token]
+	s.streamsMx.Unlock()
+
+	if !ok {
+		http.Error(w, "stream not found", http.StatusNotFound)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		s.openSSEStream(w, r, token, stream)
+
+	case http.MethodPost:
+		// operations can only be started on an open stream, otherwise their
+		// events would have nowhere to go
+		if !stream.isOpen() {
+			http.Error(w, "stream is not open", http.StatusConflict)
+			return
+		}
+
+		opts := NewRequestOptions(r)
+		opID, _ := opts.Extensions[SSEOperationIDParam].(string)
+		if opID == "" {
+			http.Error(w, "operation id is required", http.StatusBadRequest)
+			return
+		}
+
+		// reserve the operation id before starting the operation so that an
+		// id that is already active is rejected without executing anything
+		opCtx, cancelFunc := context.WithCancel(graphqlws.DetachedContext(ctx))
+		if !s.mgr.addIfAbsent(&ResultChan{
+			cancelFunc: cancelFunc,
+			ctx:        opCtx,
+			cid:        token,
+			oid:        opID,
+		}) {
+			cancelFunc()
+			http.Error(w, "operation id is already in use", http.StatusConflict)
+			return
+		}
+		results := s.startOperation(opCtx, r, opts)
+
+		go func() {
+			defer s.mgr.Del(token, opID)
+			for {
+				select {
+				case <-opCtx.Done():
+					return
+				case res, more := <-results:
+					event := sseEvent{event: sseEventComplete, data: sseStreamMessage{ID: opID}}
+					if more {
+						event = sseEvent{event: sseEventNext, data: sseStreamMessage{ID: opID, Payload: s.formatResult(res)}}
+					}
+					select {
+					case <-opCtx.Done():
+						return
+					case <-stream.done:
+						return
+					case stream.events <- event:
+					}
+					if !more {
+						return
+					}
+				}
+			}
+		}()
+
+		w.WriteHeader(http.StatusAccepted)
+
+	case http.MethodDelete:
+		opID := r.URL.Query().Get(SSEOperationIDParam)
+		if opID == "" {
+			http.Error(w, "operation id is required", http.StatusBadRequest)
+			return
+		}
+		s.mgr.Del(token, opID)
+		w.WriteHeader(http.StatusOK)
+
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// opens a reserved stream and writes events to it until the client disconnects
+func (s *Server) openSSEStream(w http.ResponseWriter, r *http.Request, token string, stream *sseStream) {
+	stream.mx.Lock()
+	if stream.released {
+		stream.mx.Unlock()
+		http.Error(w, "stream not found", http.StatusNotFound)
+		return
+	}
+	if stream.open {
+		stream.mx.Unlock()
+		http.Error(w, "stream already open", http.StatusConflict)
+		return
+	}
+	stream.open = true
+	stream.timer.Stop()
+	stream.mx.Unlock()
+
+	// release the stream and stop its operations when the client disconnects
+	defer func() {
+		stream.mx.Lock()
+		stream.released = true
+		close(stream.done)
+		stream.mx.Unlock()
+
+		s.streamsMx.Lock()
+		delete(s.streams, token)
+		s.streamsMx.Unlock()
+		s.mgr.DelConn(token)
+	}()
+
+	flusher, ok := s.startSSEResponse(w)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(s.sseKeepAlive())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if err := writeSSEComment(w, flusher); err != nil {
+				return
+			}
+		case event := <-stream.events:
+			if err := writeSSEEvent(w, flusher, event); err != nil {
+				s.log.Warnf("failed to write event to stream %s: %v", token, err)
+				return
+			}
+		}
+	}
+}
+
+// releases a reserved stream that was not opened before the reservation timeout
+func (s *Server) releaseUnopenedSSEStream(token string, stream *sseStream) {
+	stream.mx.Lock()
+	defer stream.mx.Unlock()
+
+	if stream.open || stream.released {
+		return
+	}
+	stream.released = true
+
+	s.streamsMx.Lock()
+	delete(s.streams, token)
+	s.streamsMx.Unlock()
+}
+
+// determines if a stream has been opened and not yet released
+func (stream *sseStream) isOpen() bool {
+	stream.mx.Lock()
+	defer stream.mx.Unlock()
+	return stream.open && !stream.released
+}
+
+// executes an operation and streams its results in the response
+func (s *Server) serveSSEOperation(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	opts := NewRequestOptions(r)
+	cid := uuid.New().String()
+
+	opCtx, cancelFunc := context.WithCancel(ctx)
+	results := s.startOperation(opCtx, r, opts)
+	s.mgr.Add(&ResultChan{
+		ch:         results,
+		cancelFunc: cancelFunc,
+		ctx:        opCtx,
+		cid:        cid,
+	})
+	defer s.mgr.DelConn(cid)
+
+	flusher, ok := s.startSSEResponse(w)
+	if !ok {
+		return
+	}
+
+	ticker := time.NewTicker(s.sseKeepAlive())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-opCtx.Done():
+			return
+		case <-r.Context().Done():
+			return
+		case <-ticker.C:
+			if err := writeSSEComment(w, flusher); err != nil {
+				return
+			}
+		case res, more := <-results:
+			if !more {
+				writeSSEEvent(w, flusher, sseEvent{event: sseEventComplete})
+				return
+			}
+			if err := writeSSEEvent(w, flusher, sseEvent{event: sseEventNext, data: s.formatResult(res)}); err != nil {
+				s.log.Warnf("failed to write event: %v", err)
+				return
+			}
+		}
+	}
+}
+
+// starts an operation and returns its results. Subscriptions use graphql.Subscribe
+// and other operations send a single result
+func (s *Server) startOperation(ctx context.Context, r *http.Request, opts *RequestOptions) chan *graphql.Result {
+	if err := s.resolveQuery(ctx, opts); err != nil {
+		return singleResult(persistedQueryResult(err))
+	}
+
+	if errs := s.options.Limits.Validate(s.schema, opts.Query, opts.OperationName, opts.Variables); errs != nil {
+		return singleResult(&graphql.Result{Errors: errs})
+	}
+
+	params := graphql.Params{
+		Schema:         s.schema,
+		RequestString:  opts.Query,
+		VariableValues: opts.Variables,
+		OperationName:  opts.OperationName,
+		Context:        ctx,
+	}
+	if s.options.RootValueFunc != nil {
+		params.RootObject = s.options.RootValueFunc(ctx, r)
+	}
+
+	if isSubscription(opts.Query, opts.OperationName) {
+		return graphql.Subscribe(params)
+	}
+	return singleResult(graphql.Do(params))
+}
+
+// writes the event stream headers
+func (s *Server) startSSEResponse(w http.ResponseWriter) (http.Flusher, bool) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	w.Header().Set("Content-Type", ContentTypeEventStream+"; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return flusher, true
+}
+
+// gets the keep-alive interval
+func (s *Server) sseKeepAlive() time.Duration {
+	if s.options.SSE != nil && s.options.SSE.KeepAlive > 0 {
+		return s.options.SSE.KeepAlive
+	}
+	return defaultSSEKeepAlive
+}
+
+// gets the time a reserved stream is kept before it must be opened
+func (s *Server) sseReservationTimeout() time.Duration {
+	if s.options.SSE != nil && s.options.SSE.ReservationTimeout > 0 {
+		return s.options.SSE.ReservationTimeout
+	}
+	return defaultSSEReservationTimeout
+}
+
+// writes an event
+func writeSSEEvent(w http.ResponseWriter, flusher http.Flusher, event sseEvent) error {
+	data := []byte{}
+	if event.data != nil {
+		var err error
+		if data, err = json.Marshal(event.data); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata:%s\n\n", event.event, prefixSpace(data)); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// prefixes non-empty event data with a space
+func prefixSpace(data []byte) string {
+	if len(data) == 0 {
+		return ""
+	}
+	return " " + string(data)
+}
+
+// writes a comment to keep the connection alive
+func writeSSEComment(w http.ResponseWriter, flusher http.Flusher) error {
+	if _, err := fmt.Fprint(w, ":\n\n"); err != nil {
+		return err
+	}
+	flusher.Flush()
+	return nil
+}
+
+// creates a closed channel with a single result
+func singleResult(result *graphql.Result) chan *graphql.Result {
+	ch := make(chan *graphql.Result, 1)
+	ch <- result
+	close(ch)
+	return ch
+}
+
+// determines if the operation in a query is a subscription
+func isSubscription(query, operationName string) bool {
+	document, err := parser.Parse(parser.ParseParams{Source: query})
+	if err != nil {
+		return false
+	}
+
+	op := findOperation(document, operationName)
+	return op != nil && op.Operation == ast.OperationTypeSubscription
+}
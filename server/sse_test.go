@@ -0,0 +1,228 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// reads the next event from an event stream, skipping keep-alive comments
+func readSSEEvent(t *testing.T, reader *bufio.Reader) (string, string) {
+	t.Helper()
+	event, data := "", ""
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatalf("failed to read event: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case line == "":
+			if event != "" {
+				return event, data
+			}
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+}
+
+// creates a request with a JSON body
+func newJSONRequest(t *testing.T, method, url string, v interface{}) *http.Request {
+	body, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", ContentTypeJSON)
+	return r
+}
+
+func TestSSEDistinctConnections(t *testing.T) {
+	server := newTestServer(t, &Options{SSE: &SSEOptions{}})
+	defer server.Close()
+
+	tests := []struct {
+		query  string
+		events []string
+	}{
+		{
+			query:  `subscription { count(to: 2) }`,
+			events: []string{`next {"data":{"count":1}}`, `next {"data":{"count":2}}`, `complete `},
+		},
+		{
+			query:  `{ hello }`,
+			events: []string{`next {"data":{"hello":"world"}}`, `complete `},
+		},
+	}
+
+	for _, test := range tests {
+		r := newJSONRequest(t, http.MethodPost, server.URL, map[string]interface{}{"query": test.query})
+		r.Header.Set("Accept", ContentTypeEventStream)
+		res, body := doRequest(t, r)
+
+		if contentType := res.Header.Get("Content-Type"); !strings.HasPrefix(contentType, ContentTypeEventStream) {
+			t.Errorf("expected content type %s, got %s", ContentTypeEventStream, contentType)
+		}
+
+		reader := bufio.NewReader(bytes.NewReader(body))
+		for _, expected := range test.events {
+			event, data := readSSEEvent(t, reader)
+			if actual := event + " " + data; actual != expected {
+				t.Errorf("%s: expected event %q, got %q", test.query, expected, actual)
+			}
+		}
+	}
+}
+
+func TestSSESingleConnection(t *testing.T) {
+	server := newTestServer(t, &Options{SSE: &SSEOptions{}})
+	defer server.Close()
+
+	// reserve a stream
+	r, _ := http.NewRequest(http.MethodPut, server.URL, nil)
+	res, body := doRequest(t, r)
+	if res.StatusCode != http.StatusCreated {
+		t.Fatalf("expected status %d, got %d", http.StatusCreated, res.StatusCode)
+	}
+	token := string(body)
+
+	subscribe := func() *http.Response {
+		r := newJSONRequest(t, http.MethodPost, server.URL, map[string]interface{}{
+			"query":      `subscription { count(to: 2) }`,
+			"extensions": map[string]interface{}{SSEOperationIDParam: "op1"},
+		})
+		r.Header.Set(SSETokenHeader, token)
+		res, _ := doRequest(t, r)
+		return res
+	}
+
+	// operations cannot be started before the stream is opened
+	if res := subscribe(); res.StatusCode != http.StatusConflict {
+		t.Errorf("expected status %d before opening the stream, got %d", http.StatusConflict, res.StatusCode)
+	}
+
+	// open the stream
+	r, _ = http.NewRequest(http.MethodGet, server.URL+"?"+SSETokenParam+"="+token, nil)
+	r.Header.Set("Accept", ContentTypeEventStream)
+	stream, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+
+	// the stream can only be opened once
+	r, _ = http.NewRequest(http.MethodGet, server.URL+"?"+SSETokenParam+"="+token, nil)
+	if res, _ := doRequest(t, r); res.StatusCode != http.StatusConflict {
+		t.Errorf("expected status %d opening the stream twice, got %d", http.StatusConflict, res.StatusCode)
+	}
+
+	if res := subscribe(); res.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, res.StatusCode)
+	}
+
+	reader := bufio.NewReader(stream.Body)
+	for _, expected := range []string{
+		`next {"id":"op1","payload":{"data":{"count":1}}}`,
+		`next {"id":"op1","payload":{"data":{"count":2}}}`,
+		`complete {"id":"op1"}`,
+	} {
+		event, data := readSSEEvent(t, reader)
+		if actual := event + " " + data; actual != expected {
+			t.Errorf("expected event %q, got %q", expected, actual)
+		}
+	}
+
+	// unknown streams are not found
+	r, _ = http.NewRequest(http.MethodGet, server.URL+"?"+SSETokenParam+"=unknown", nil)
+	if res, _ := doRequest(t, r); res.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status %d for an unknown stream, got %d", http.StatusNotFound, res.StatusCode)
+	}
+}
+
+func TestSSEReservationTimeout(t *testing.T) {
+	s := New(testSchema(t), &Options{SSE: &SSEOptions{ReservationTimeout: 50 * time.Millisecond}})
+
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/", nil))
+	token := w.Body.String()
+
+	s.streamsMx.Lock()
+	reserved := len(s.streams)
+	s.streamsMx.Unlock()
+	if reserved != 1 {
+		t.Fatalf("expected 1 reserved stream, got %d", reserved)
+	}
+
+	// the stream is released once the reservation times out
+	time.Sleep(150 * time.Millisecond)
+	s.streamsMx.Lock()
+	reserved = len(s.streams)
+	s.streamsMx.Unlock()
+	if reserved != 0 {
+		t.Errorf("expected the unopened stream to be released, got %d reserved", reserved)
+	}
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/?"+SSETokenParam+"="+token, nil)
+	r.Header.Set("Accept", ContentTypeEventStream)
+	s.ServeHTTP(w, r)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected status %d for a released stream, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestSSEDuplicateOperationID(t *testing.T) {
+	server := newTestServer(t, &Options{SSE: &SSEOptions{}})
+	defer server.Close()
+
+	r, _ := http.NewRequest(http.MethodPut, server.URL, nil)
+	_, body := doRequest(t, r)
+	token := string(body)
+
+	r, _ = http.NewRequest(http.MethodGet, server.URL+"?"+SSETokenParam+"="+token, nil)
+	r.Header.Set("Accept", ContentTypeEventStream)
+	stream, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stream.Body.Close()
+
+	subscribe := func() int {
+		r := newJSONRequest(t, http.MethodPost, server.URL, map[string]interface{}{
+			"query":      `subscription { count(to: 1000000000) }`,
+			"extensions": map[string]interface{}{SSEOperationIDParam: "op1"},
+		})
+		r.Header.Set(SSETokenHeader, token)
+		res, _ := doRequest(t, r)
+		return res.StatusCode
+	}
+
+	// the first operation stays active while it counts
+	if status := subscribe(); status != http.StatusAccepted {
+		t.Fatalf("expected status %d, got %d", http.StatusAccepted, status)
+	}
+	if status := subscribe(); status != http.StatusConflict {
+		t.Errorf("expected status %d for a duplicate operation id, got %d", http.StatusConflict, status)
+	}
+
+	// the id can be reused once the operation is stopped
+	r, _ = http.NewRequest(http.MethodDelete, server.URL+"?"+SSEOperationIDParam+"=op1", nil)
+	r.Header.Set(SSETokenHeader, token)
+	if res, _ := doRequest(t, r); res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, res.StatusCode)
+	}
+	if status := subscribe(); status != http.StatusAccepted {
+		t.Errorf("expected status %d after stopping the operation, got %d", http.StatusAccepted, status)
+	}
+}
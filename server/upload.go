@@ -0,0 +1,223 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bhoriuchi/graphql-go-tools/scalars"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// Constants
+const (
+	ContentTypeMultipartFormData = "multipart/form-data"
+
+	defaultUploadMaxMemory = 32 << 20
+)
+
+// UploadOptions options for the graphql multipart request spec
+type UploadOptions struct {
+	MaxMemory      int64 // maximum bytes of files stored in memory, the rest are stored on disk, defaults to 32MB
+	MaxFileSize    int64 // maximum size of an uploaded file, 0 is unlimited
+	MaxRequestSize int64 // maximum size of the request body, defaults to MaxMemory plus MaxFileSize or unlimited when MaxFileSize is 0
+}
+
+// IsMultipartRequest determines if a request is a multipart form request
+func IsMultipartRequest(r *http.Request) bool {
+	return r.Method == http.MethodPost &&
+		strings.HasPrefix(r.Header.Get("Content-Type"), ContentTypeMultipartFormData)
+}
+
+// NewMultipartRequestOptions Parses a http.Request using the graphql multipart request spec.
+// The operations field is parsed into one or more request options and the files
+// referenced by the map field are set in their variables as *scalars.Upload values.
+// If operations is an array the request is a batch and true is returned. The returned
+// files should be closed once the operations have been executed
+func NewMultipartRequestOptions(r *http.Request, options UploadOptions) ([]*RequestOptions, bool, []multipart.File, error) {
+	maxMemory := options.MaxMemory
+	if maxMemory <= 0 {
+		maxMemory = defaultUploadMaxMemory
+	}
+	maxRequestSize := options.MaxRequestSize
+	if maxRequestSize <= 0 && options.MaxFileSize > 0 {
+		maxRequestSize = maxMemory + options.MaxFileSize
+	}
+
+	// limit the body before parsing so oversized requests are not written to disk
+	if maxRequestSize > 0 {
+		r.Body = http.MaxBytesReader(nil, r.Body, maxRequestSize)
+	}
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		return nil, false, nil, fmt.Errorf("failed to parse multipart form: %v", err)
+	}
+
+	// parse the operations
+	operations := strings.TrimSpace(r.FormValue("operations"))
+	if operations == "" {
+		return nil, false, nil, fmt.Errorf("missing multipart field: operations")
+	}
+
+	batch := []*RequestOptions{}
+	isBatch := strings.HasPrefix(operations, "[")
+	if isBatch {
+		items := []json.RawMessage{}
+		if err := json.Unmarshal([]byte(operations), &items); err != nil {
+			return nil, false, nil, fmt.Errorf("invalid multipart field operations: %v", err)
+		}
+		for _, item := range items {
+			batch = append(batch, unmarshalRequestOptions(item))
+		}
+	} else {
+		opts := RequestOptions{}
+		if err := json.Unmarshal([]byte(operations), &opts); err != nil {
+			return nil, false, nil, fmt.Errorf("invalid multipart field operations: %v", err)
+		}
+		batch = append(batch, &opts)
+	}
+
+	// parse the map of files to variable paths
+	fileMap := map[string][]string{}
+	if value := r.FormValue("map"); value != "" {
+		if err := json.Unmarshal([]byte(value), &fileMap); err != nil {
+			return nil, false, nil, fmt.Errorf("invalid multipart field map: %v", err)
+		}
+	}
+
+	files := []multipart.File{}
+	closeFiles := func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}
+
+	for key, paths := range fileMap {
+		headers := r.MultipartForm.File[key]
+		if len(headers) == 0 {
+			closeFiles()
+			return nil, false, nil, fmt.Errorf("missing file for multipart field %q", key)
+		}
+
+		header := headers[0]
+		if options.MaxFileSize > 0 && header.Size > options.MaxFileSize {
+			closeFiles()
+			return nil, false, nil, fmt.Errorf("file %q exceeds the maximum size of %d bytes", header.Filename, options.MaxFileSize)
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			closeFiles()
+			return nil, false, nil, fmt.Errorf("failed to open file %q: %v", header.Filename, err)
+		}
+		files = append(files, file)
+
+		upload := &scalars.Upload{
+			File:        file,
+			Filename:    header.Filename,
+			ContentType: header.Header.Get("Content-Type"),
+			Size:        header.Size,
+		}
+
+		for _, path := range paths {
+			if err := setUploadPath(batch, isBatch, path, upload); err != nil {
+				closeFiles()
+				return nil, false, nil, err
+			}
+		}
+	}
+
+	return batch, isBatch, files, nil
+}
+
+// sets an upload at an object path like variables.files.0 or 0.variables.file in a batch
+func setUploadPath(batch []*RequestOptions, isBatch bool, path string, upload *scalars.Upload) error {
+	segments := strings.Split(path, ".")
+
+	index := 0
+	if isBatch {
+		i, err := strconv.Atoi(segments[0])
+		if err != nil || i < 0 || i >= len(batch) {
+			return fmt.Errorf("invalid upload path %q", path)
+		}
+		index, segments = i, segments[1:]
+	}
+
+	if len(segments) < 2 || segments[0] != "variables" {
+		return fmt.Errorf("invalid upload path %q", path)
+	}
+
+	opts := batch[index]
+	if opts.Variables == nil {
+		opts.Variables = map[string]interface{}{}
+	}
+
+	var container interface{} = opts.Variables
+	for i, segment := range segments[1:] {
+		last := i == len(segments)-2
+
+		switch c := container.(type) {
+		case map[string]interface{}:
+			if last {
+				c[segment] = upload
+				return nil
+			}
+			container = c[segment]
+
+		case []interface{}:
+			j, err := strconv.Atoi(segment)
+			if err != nil || j < 0 || j >= len(c) {
+				return fmt.Errorf("invalid upload path %q", path)
+			}
+			if last {
+				c[j] = upload
+				return nil
+			}
+			container = c[j]
+
+		default:
+			return fmt.Errorf("invalid upload path %q", path)
+		}
+	}
+
+	return nil
+}
+
+// executes a multipart request with file uploads
+func (s *Server) uploadHandler(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	batch, isBatch, files, err := NewMultipartRequestOptions(r, *s.options.Upload)
+	if r.MultipartForm != nil {
+		defer r.MultipartForm.RemoveAll()
+	}
+	if err != nil {
+		s.writeJSON(w, http.StatusBadRequest, &graphql.Result{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+	defer func() {
+		for _, file := range files {
+			file.Close()
+		}
+	}()
+
+	if isBatch {
+		if s.options.Batch == nil {
+			s.writeJSON(w, http.StatusBadRequest, &graphql.Result{
+				Errors: gqlerrors.FormatErrors(fmt.Errorf("batched requests are not enabled")),
+			})
+			return
+		}
+		s.batchHandler(ctx, w, r, ContentTypeJSON, batch, s.execute)
+		return
+	}
+
+	params, result := s.execute(ctx, r, batch[0])
+	buff := s.writeJSON(w, http.StatusOK, result)
+
+	if s.options.ResultCallbackFunc != nil {
+		s.options.ResultCallbackFunc(ctx, &params, result, buff)
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/bhoriuchi/graphql-go-tools/scalars"
+)
+
+// creates a multipart request following the graphql multipart request spec
+func newMultipartRequest(t *testing.T, url, operations, fileMap string, files map[string]string) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	writer.WriteField("operations", operations)
+	writer.WriteField("map", fileMap)
+	for name, content := range files {
+		part, err := writer.CreateFormFile(name, name+".txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write([]byte(content))
+	}
+	writer.Close()
+
+	r, err := http.NewRequest(http.MethodPost, url, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("Content-Type", writer.FormDataContentType())
+	return r
+}
+
+func TestUpload(t *testing.T) {
+	server := newTestServer(t, &Options{
+		Upload: &UploadOptions{MaxFileSize: 16},
+		Batch:  &BatchOptions{},
+	})
+	defer server.Close()
+
+	query := `query ($file: Upload) { upload(file: $file) }`
+
+	// a single operation
+	_, body := doRequest(t, newMultipartRequest(t, server.URL,
+		`{"query":"`+query+`","variables":{"file":null}}`,
+		`{"a":["variables.file"]}`,
+		map[string]string{"a": "hello"},
+	))
+	assertJSON(t, body, `{"data":{"upload":"a.txt:hello"}}`)
+
+	// a batch of operations
+	_, body = doRequest(t, newMultipartRequest(t, server.URL,
+		`[{"query":"`+query+`"},{"query":"`+query+`"}]`,
+		`{"a":["0.variables.file"],"b":["1.variables.file"]}`,
+		map[string]string{"a": "first", "b": "second"},
+	))
+	assertJSON(t, body, `[{"data":{"upload":"a.txt:first"}},{"data":{"upload":"b.txt:second"}}]`)
+
+	// an invalid path
+	res, _ := doRequest(t, newMultipartRequest(t, server.URL,
+		`{"query":"`+query+`"}`,
+		`{"a":["query.file"]}`,
+		map[string]string{"a": "hello"},
+	))
+	if res.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status %d for an invalid path, got %d", http.StatusBadRequest, res.StatusCode)
+	}
+}
+
+func TestUploadMaxSize(t *testing.T) {
+	server := newTestServer(t, &Options{
+		Upload: &UploadOptions{MaxMemory: 1024, MaxFileSize: 16},
+	})
+	defer server.Close()
+
+	query := `{"query":"query ($file: Upload) { upload(file: $file) }"}`
+
+	tests := []struct {
+		name    string
+		content string
+		message string
+	}{
+		// the file exceeds the file size but the request is within the limit
+		{name: "file size", content: strings.Repeat("a", 32), message: "exceeds the maximum size"},
+		// the request exceeds the memory and file size limits
+		{name: "request size", content: strings.Repeat("a", 4096), message: "request body too large"},
+	}
+
+	for _, test := range tests {
+		res, body := doRequest(t, newMultipartRequest(t, server.URL, query,
+			`{"a":["variables.file"]}`,
+			map[string]string{"a": test.content},
+		))
+		if res.StatusCode != http.StatusBadRequest {
+			t.Errorf("%s: expected status %d, got %d", test.name, http.StatusBadRequest, res.StatusCode)
+		}
+		if !strings.Contains(string(body), test.message) {
+			t.Errorf("%s: expected error containing %q, got %s", test.name, test.message, body)
+		}
+	}
+}
+
+func TestUploadDefaultOptions(t *testing.T) {
+	// files larger than the default maximum memory are stored on disk
+	content := strings.Repeat("a", defaultUploadMaxMemory+1024)
+	r := newMultipartRequest(t, "/graphql",
+		`{"query":"query ($file: Upload) { upload(file: $file) }"}`,
+		`{"a":["variables.file"]}`,
+		map[string]string{"a": content},
+	)
+
+	batch, _, files, err := NewMultipartRequestOptions(r, UploadOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.MultipartForm.RemoveAll()
+	for _, file := range files {
+		defer file.Close()
+	}
+
+	upload, ok := batch[0].Variables["file"].(*scalars.Upload)
+	if !ok {
+		t.Fatalf("expected an upload variable, got %v", batch[0].Variables["file"])
+	}
+	if upload.Size != int64(len(content)) {
+		t.Errorf("expected an upload of %d bytes, got %d", len(content), upload.Size)
+	}
+}
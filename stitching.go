@@ -0,0 +1,268 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// MergeSchemas is shorthand for MergeSchemasWithConfig with TypeDefs and Resolvers
+func MergeSchemas(schemas []graphql.Schema, typeDefs interface{}, resolvers map[string]interface{}) (graphql.Schema, error) {
+	return MergeSchemasWithConfig(context.Background(), schemas, ExecutableSchema{
+		TypeDefs:  typeDefs,
+		Resolvers: resolvers,
+	})
+}
+
+// MergeSchemasWithConfig combines the root Query, Mutation, and Subscription fields
+// of each schema into a single gateway schema. The types of each schema are converted
+// into type definitions so that the config TypeDefs can extend and link them and the
+// config Resolvers can resolve the linking fields. Fields of the merged types delegate
+// their execution to the resolvers of the schema that owns them. When more than one
+// schema defines a type or root field with the same name the first definition is used
+func MergeSchemasWithConfig(ctx context.Context, schemas []graphql.Schema, config ExecutableSchema) (graphql.Schema, error) {
+	merger := &schemaMerger{
+		document:  ast.NewDocument(&ast.Document{Definitions: []ast.Node{}}),
+		resolvers: map[string]interface{}{},
+		types:     map[string]bool{},
+		roots: map[string]*ast.ObjectDefinition{
+			DefaultRootQueryName:        nil,
+			DefaultRootMutationName:     nil,
+			DefaultRootSubscriptionName: nil,
+		},
+	}
+
+	for _, schema := range schemas {
+		merger.addSchema(schema)
+	}
+
+	// add the merged root types
+	for _, name := range []string{DefaultRootQueryName, DefaultRootMutationName, DefaultRootSubscriptionName} {
+		if root := merger.roots[name]; root != nil {
+			merger.document.Definitions = append(merger.document.Definitions, root)
+		}
+	}
+
+	// add the linking type definitions
+	if config.TypeDefs != nil {
+		document, err := config.ConcatenateTypeDefs()
+		if err != nil {
+			return graphql.Schema{}, err
+		}
+		merger.document.Definitions = append(merger.document.Definitions, document.Definitions...)
+	}
+
+	// add the linking resolvers, these take precedence over the merged resolvers
+	for name, resolver := range config.Resolvers {
+		merger.addResolver(name, resolver)
+	}
+
+	config.TypeDefs = merger.document
+	config.Resolvers = merger.resolvers
+	return config.Make(ctx)
+}
+
+// schemaMerger tracks the state of a schema merge
+type schemaMerger struct {
+	document  *ast.Document
+	resolvers map[string]interface{}
+	types     map[string]bool
+	roots     map[string]*ast.ObjectDefinition
+}
+
+// adds the types, directives, and root fields of a schema
+func (c *schemaMerger) addSchema(schema graphql.Schema) {
+	rootTypes := map[string]*graphql.Object{
+		DefaultRootQueryName:        schema.QueryType(),
+		DefaultRootMutationName:     schema.MutationType(),
+		DefaultRootSubscriptionName: schema.SubscriptionType(),
+	}
+
+	rootNames := map[string]bool{}
+	for _, root := range rootTypes {
+		if root != nil {
+			rootNames[root.Name()] = true
+		}
+	}
+
+	for _, directive := range schema.Directives() {
+		if isBuiltinDirective(directive) || c.types["@"+directive.Name] {
+			continue
+		}
+		c.types["@"+directive.Name] = true
+		c.document.Definitions = append(c.document.Definitions, astFromDirective(directive))
+	}
+
+	typeMap := schema.TypeMap()
+	for _, name := range sortedTypeNames(typeMap) {
+		if c.types[name] || rootNames[name] || isSpecifiedScalar(name) || isIntrospectionType(name) {
+			continue
+		}
+
+		t := typeMap[name]
+		if definition := astFromType(t); definition != nil {
+			c.types[name] = true
+			c.document.Definitions = append(c.document.Definitions, definition)
+			c.addTypeResolver(t)
+		}
+	}
+
+	for rootName, root := range rootTypes {
+		if root != nil {
+			c.addRootFields(schema, rootName, root)
+		}
+	}
+}
+
+// adds the fields of a schema root type to the merged root type
+func (c *schemaMerger) addRootFields(schema graphql.Schema, rootName string, root *graphql.Object) {
+	merged := c.roots[rootName]
+	if merged == nil {
+		merged = ast.NewObjectDefinition(&ast.ObjectDefinition{
+			Name:        astName(rootName),
+			Description: astDescription(root.PrivateDescription),
+			Interfaces:  []*ast.Named{},
+			Directives:  []*ast.Directive{},
+			Fields:      []*ast.FieldDefinition{},
+		})
+		c.roots[rootName] = merged
+	}
+
+	resolver := c.objectResolver(rootName)
+	existing := map[string]bool{}
+	for _, field := range merged.Fields {
+		existing[field.Name.Value] = true
+	}
+
+	for _, fieldDef := range astFromFieldMap(root.Fields()) {
+		fieldName := fieldDef.Name.Value
+		if existing[fieldName] {
+			continue
+		}
+		merged.Fields = append(merged.Fields, fieldDef)
+
+		field := root.Fields()[fieldName]
+		resolver.Fields[fieldName] = &FieldResolve{
+			Resolve:   delegateFieldResolveFn(schema, field.Resolve),
+			Subscribe: delegateFieldResolveFn(schema, field.Subscribe),
+		}
+	}
+}
+
+// gets or creates the object resolver for a type
+func (c *schemaMerger) objectResolver(name string) *ObjectResolver {
+	if resolver, ok := c.resolvers[name].(*ObjectResolver); ok {
+		return resolver
+	}
+	resolver := &ObjectResolver{
+		Fields: FieldResolveMap{},
+	}
+	c.resolvers[name] = resolver
+	return resolver
+}
+
+// adds a resolver that maps the type back to the resolve functions of the source schema
+func (c *schemaMerger) addTypeResolver(t graphql.Type) {
+	switch tt := t.(type) {
+	case *graphql.Scalar:
+		c.resolvers[tt.Name()] = &ScalarResolver{
+			Serialize:    tt.Serialize,
+			ParseValue:   tt.ParseValue,
+			ParseLiteral: tt.ParseLiteral,
+		}
+
+	case *graphql.Enum:
+		values := map[string]interface{}{}
+		for _, value := range tt.Values() {
+			values[value.Name] = value.Value
+		}
+		c.resolvers[tt.Name()] = &EnumResolver{
+			Values: values,
+		}
+
+	case *graphql.Object:
+		resolver := c.objectResolver(tt.Name())
+		resolver.IsTypeOf = tt.IsTypeOf
+		for name, field := range tt.Fields() {
+			if field.Resolve != nil || field.Subscribe != nil {
+				resolver.Fields[name] = &FieldResolve{
+					Resolve:   field.Resolve,
+					Subscribe: field.Subscribe,
+				}
+			}
+		}
+
+	case *graphql.Interface:
+		c.resolvers[tt.Name()] = &InterfaceResolver{
+			ResolveType: mergedResolveTypeFn(tt.ResolveType),
+			Fields:      FieldResolveMap{},
+		}
+
+	case *graphql.Union:
+		c.resolvers[tt.Name()] = &UnionResolver{
+			ResolveType: mergedResolveTypeFn(tt.ResolveType),
+		}
+	}
+}
+
+// adds a user supplied resolver, object and interface field resolvers are
+// merged into any existing resolvers
+func (c *schemaMerger) addResolver(name string, resolver interface{}) {
+	switch res := resolver.(type) {
+	case *ObjectResolver:
+		if existing, ok := c.resolvers[name].(*ObjectResolver); ok {
+			if res.IsTypeOf != nil {
+				existing.IsTypeOf = res.IsTypeOf
+			}
+			for fieldName, fieldResolve := range res.Fields {
+				existing.Fields[fieldName] = fieldResolve
+			}
+			return
+		}
+
+	case *InterfaceResolver:
+		if existing, ok := c.resolvers[name].(*InterfaceResolver); ok {
+			if res.ResolveType != nil {
+				existing.ResolveType = res.ResolveType
+			}
+			for fieldName, fieldResolve := range res.Fields {
+				existing.Fields[fieldName] = fieldResolve
+			}
+			return
+		}
+	}
+
+	c.resolvers[name] = resolver
+}
+
+// wraps a root field resolve function so that it executes with the schema that owns it
+func delegateFieldResolveFn(schema graphql.Schema, fn graphql.FieldResolveFn) graphql.FieldResolveFn {
+	if fn == nil {
+		return nil
+	}
+
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		p.Info.Schema = schema
+		return fn(p)
+	}
+}
+
+// wraps a resolve type function so that the object type returned from the
+// source schema is swapped for the object type of the same name in the merged schema
+func mergedResolveTypeFn(fn graphql.ResolveTypeFn) graphql.ResolveTypeFn {
+	if fn == nil {
+		return nil
+	}
+
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		object := fn(p)
+		if object == nil {
+			return nil
+		}
+		if t, ok := p.Info.Schema.Type(object.Name()).(*graphql.Object); ok {
+			return t
+		}
+		return nil
+	}
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+)
+
+func TestMergeSchemas(t *testing.T) {
+	users := []map[string]interface{}{
+		{"id": "1", "name": "User1", "role": "ADMIN"},
+		{"id": "2", "name": "User2", "role": "USER"},
+	}
+
+	posts := []map[string]interface{}{
+		{"id": "10", "title": "Post1", "authorId": "1"},
+		{"id": "11", "title": "Post2", "authorId": "2"},
+	}
+
+	userSchema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+enum Role {
+	ADMIN
+	USER
+}
+
+type User {
+	id: ID!
+	name: String
+	role: Role
+}
+
+type Query {
+	user(id: ID!): User
+}`,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"user": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							for _, user := range users {
+								if user["id"] == p.Args["id"] {
+									return user, nil
+								}
+							}
+							return nil, nil
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make user schema: %v", err)
+		return
+	}
+
+	postSchema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: `
+type Post {
+	id: ID!
+	title: String
+	authorId: ID
+}
+
+type Query {
+	posts: [Post]
+}`,
+		Resolvers: ResolverMap{
+			"Query": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"posts": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							return posts, nil
+						},
+					},
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make post schema: %v", err)
+		return
+	}
+
+	schema, err := MergeSchemas(
+		[]graphql.Schema{userSchema, postSchema},
+		`extend type Post {
+			author: User
+		}`,
+		ResolverMap{
+			"Post": &ObjectResolver{
+				Fields: FieldResolveMap{
+					"author": &FieldResolve{
+						Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+							post := p.Source.(map[string]interface{})
+							return userSchema.QueryType().Fields()["user"].Resolve(graphql.ResolveParams{
+								Args: map[string]interface{}{"id": post["authorId"]},
+							})
+						},
+					},
+				},
+			},
+		},
+	)
+	if err != nil {
+		t.Errorf("failed to merge schemas: %v", err)
+		return
+	}
+
+	r := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `query {
+			user(id: "1") {
+				name
+				role
+			}
+			posts {
+				title
+				author {
+					name
+				}
+			}
+		}`,
+	})
+
+	if r.HasErrors() {
+		t.Error(r.Errors)
+		return
+	}
+
+	d := r.Data.(map[string]interface{})
+	if role := d["user"].(map[string]interface{})["role"]; role != "ADMIN" {
+		t.Errorf("expected delegated user role ADMIN, got %v", role)
+		return
+	}
+
+	post1 := d["posts"].([]interface{})[1].(map[string]interface{})
+	if name := post1["author"].(map[string]interface{})["name"]; name != "User2" {
+		t.Errorf("expected linked author User2, got %v", name)
+		return
+	}
+}
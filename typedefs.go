@@ -5,8 +5,6 @@ import (
 	"strings"
 
 	"github.com/graphql-go/graphql/language/ast"
-	"github.com/graphql-go/graphql/language/parser"
-	"github.com/graphql-go/graphql/language/printer"
 	"github.com/graphql-go/graphql/language/source"
 )
 
@@ -19,8 +17,10 @@ func (c *ExecutableSchema) ConcatenateTypeDefs() (*ast.Document, error) {
 		return c.concatenateTypeDefs(c.TypeDefs.([]string))
 	case func() []string:
 		return c.concatenateTypeDefs(c.TypeDefs.(func() []string)())
+	case *ast.Document:
+		return c.TypeDefs.(*ast.Document), nil
 	}
-	return nil, fmt.Errorf("unsupported TypeDefs value. Must be one of string, []string, or func() []string")
+	return nil, fmt.Errorf("unsupported TypeDefs value. Must be one of string, []string, func() []string, or *ast.Document")
 }
 
 // performs the actual concatenation of the types by parsing each
@@ -30,11 +30,9 @@ func (c *ExecutableSchema) ConcatenateTypeDefs() (*ast.Document, error) {
 func (c *ExecutableSchema) concatenateTypeDefs(typeDefs []string) (*ast.Document, error) {
 	resolvedTypes := map[string]interface{}{}
 	for _, defs := range typeDefs {
-		doc, err := parser.Parse(parser.ParseParams{
-			Source: &source.Source{
-				Body: []byte(defs),
-				Name: "GraphQL",
-			},
+		doc, err := parseTypeDefs(&source.Source{
+			Body: []byte(defs),
+			Name: "GraphQL",
 		})
 		if err != nil {
 			return nil, err
@@ -46,7 +44,7 @@ func (c *ExecutableSchema) concatenateTypeDefs(typeDefs []string) (*ast.Document
 		}
 
 		for _, typeDef := range doc.Definitions {
-			if def := printer.Print(typeDef); def != nil {
+			if def := printTypeDef(typeDef); def != nil {
 				stringDef := strings.TrimSpace(def.(string))
 				resolvedTypes[stringDef] = nil
 			}
@@ -58,11 +56,9 @@ func (c *ExecutableSchema) concatenateTypeDefs(typeDefs []string) (*ast.Document
 		typeArray = append(typeArray, def)
 	}
 
-	doc, err := parser.Parse(parser.ParseParams{
-		Source: &source.Source{
-			Body: []byte(strings.Join(typeArray, "\n")),
-			Name: "GraphQL",
-		},
+	doc, err := parseTypeDefs(&source.Source{
+		Body: []byte(strings.Join(typeArray, "\n")),
+		Name: "GraphQL",
 	})
 
 	if err != nil {
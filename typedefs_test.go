@@ -173,3 +173,133 @@ func TestUnionResolveType(t *testing.T) {
 		return
 	}
 }
+
+func TestTypeExtensions(t *testing.T) {
+	visited := map[string]int{}
+	schema, err := MakeExecutableSchema(ExecutableSchema{
+		TypeDefs: []string{
+			`
+			directive @tag(name: String) on SCALAR | INTERFACE | UNION | ENUM | INPUT_OBJECT
+
+			scalar Date
+
+			interface Node {
+				id: ID!
+			}
+
+			type Foo implements Node {
+				id: ID!
+				name: String
+			}
+
+			type Bar implements Node {
+				id: ID!
+			}
+
+			union Thing = Foo
+
+			enum Color {
+				RED
+			}
+
+			input Filter {
+				name: String
+			}
+
+			type Query {
+				things(filter: Filter): [Thing]
+				nodes: [Node]
+				color: Color
+			}`,
+			`
+			extend scalar Date @tag(name: "scalar")
+
+			extend interface Node @tag(name: "interface") {
+				name: String
+			}
+
+			extend union Thing @tag(name: "union") = Bar
+
+			extend enum Color @tag(name: "enum") {
+				GREEN
+			}
+
+			extend input Filter @tag(name: "input") {
+				color: Color
+			}
+
+			extend type Bar {
+				name: String
+			}`,
+		},
+		Resolvers: ResolverMap{
+			"Date": &ScalarResolver{
+				Serialize: func(value interface{}) interface{} {
+					return value
+				},
+			},
+			"Thing": &UnionResolver{
+				ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+					return nil
+				},
+			},
+			"Node": &InterfaceResolver{
+				ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+					return nil
+				},
+			},
+		},
+		SchemaDirectives: SchemaDirectiveVisitorMap{
+			"tag": &SchemaDirectiveVisitor{
+				VisitScalar: func(p VisitScalarParams) error {
+					visited[p.Args["name"].(string)] = len(p.Extensions)
+					return nil
+				},
+				VisitInterface: func(p VisitInterfaceParams) error {
+					visited[p.Args["name"].(string)] = len(p.Extensions)
+					return nil
+				},
+				VisitUnion: func(p VisitUnionParams) error {
+					visited[p.Args["name"].(string)] = len(p.Extensions)
+					return nil
+				},
+				VisitEnum: func(p VisitEnumParams) error {
+					visited[p.Args["name"].(string)] = len(p.Extensions)
+					return nil
+				},
+				VisitInputObject: func(p VisitInputObjectParams) error {
+					visited[p.Args["name"].(string)] = len(p.Extensions)
+					return nil
+				},
+			},
+		},
+	})
+	if err != nil {
+		t.Errorf("failed to make schema: %v", err)
+		return
+	}
+
+	for _, name := range []string{"scalar", "interface", "union", "enum", "input"} {
+		if visited[name] != 1 {
+			t.Errorf("expected %s directive visitor to receive 1 extension, got %d", name, visited[name])
+			return
+		}
+	}
+
+	if _, ok := schema.Type("Node").(*graphql.Interface).Fields()["name"]; !ok {
+		t.Error("expected interface Node to be extended with field name")
+		return
+	}
+	if types := schema.Type("Thing").(*graphql.Union).Types(); len(types) != 2 {
+		t.Errorf("expected union Thing to have 2 members, got %d", len(types))
+		return
+	}
+	if values := schema.Type("Color").(*graphql.Enum).Values(); len(values) != 2 {
+		t.Errorf("expected enum Color to have 2 values, got %d", len(values))
+		return
+	}
+	if _, ok := schema.Type("Filter").(*graphql.InputObject).Fields()["color"]; !ok {
+		t.Error("expected input Filter to be extended with field color")
+		return
+	}
+}
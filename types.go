@@ -11,6 +11,7 @@ import (
 // builds a scalar from ast
 func (c *registry) buildScalarFromAST(definition *ast.ScalarDefinition) error {
 	name := definition.Name.Value
+	extensions := c.getScalarExtensions(name)
 	scalarConfig := graphql.ScalarConfig{
 		Name:        name,
 		Description: getDescription(definition),
@@ -22,9 +23,24 @@ func (c *registry) buildScalarFromAST(definition *ast.ScalarDefinition) error {
 		scalarConfig.Serialize = r.(*ScalarResolver).Serialize
 	}
 
+	// update description from extensions if none
+	for _, extDef := range extensions {
+		if scalarConfig.Description != "" {
+			break
+		}
+		scalarConfig.Description = getDescription(extDef)
+	}
+
+	// create a combined directives array
+	directiveDefs := append([]*ast.Directive{}, definition.Directives...)
+	for _, extDef := range extensions {
+		directiveDefs = append(directiveDefs, extDef.Directives...)
+	}
+
 	if err := c.applyDirectives(applyDirectiveParams{
 		config:     &scalarConfig,
-		directives: definition.Directives,
+		directives: directiveDefs,
+		extensions: extensions,
 		node:       definition,
 	}); err != nil {
 		return err
@@ -37,14 +53,24 @@ func (c *registry) buildScalarFromAST(definition *ast.ScalarDefinition) error {
 // builds an enum from ast
 func (c *registry) buildEnumFromAST(definition *ast.EnumDefinition) error {
 	name := definition.Name.Value
+	extensions := c.getEnumExtensions(name)
 	enumConfig := graphql.EnumConfig{
 		Name:        name,
 		Description: getDescription(definition),
 		Values:      graphql.EnumValueConfigMap{},
 	}
 
-	for _, value := range definition.Values {
-		if value != nil {
+	// build list of values and append extensions
+	valueDefs := append([]*ast.EnumValueDefinition{}, definition.Values...)
+	for _, extDef := range extensions {
+		valueDefs = append(valueDefs, extDef.Values...)
+	}
+
+	for _, value := range valueDefs {
+		if value == nil {
+			continue
+		}
+		if _, ok := enumConfig.Values[value.Name.Value]; !ok {
 			val, err := c.buildEnumValueFromAST(value, name)
 			if err != nil {
 				return err
@@ -53,9 +79,24 @@ func (c *registry) buildEnumFromAST(definition *ast.EnumDefinition) error {
 		}
 	}
 
+	// update description from extensions if none
+	for _, extDef := range extensions {
+		if enumConfig.Description != "" {
+			break
+		}
+		enumConfig.Description = getDescription(extDef)
+	}
+
+	// create a combined directives array
+	directiveDefs := append([]*ast.Directive{}, definition.Directives...)
+	for _, extDef := range extensions {
+		directiveDefs = append(directiveDefs, extDef.Directives...)
+	}
+
 	if err := c.applyDirectives(applyDirectiveParams{
 		config:     &enumConfig,
-		directives: definition.Directives,
+		directives: directiveDefs,
+		extensions: extensions,
 		node:       definition,
 	}); err != nil {
 		return err
@@ -77,14 +118,17 @@ func (c *registry) buildEnumValueFromAST(definition *ast.EnumValueDefinition, en
 	}
 
 	valueConfig := graphql.EnumValueConfig{
-		Value:       value,
-		Description: getDescription(definition),
+		Value:             value,
+		Description:       getDescription(definition),
+		DeprecationReason: getDeprecationReason(definition.Directives),
 	}
 
 	if err := c.applyDirectives(applyDirectiveParams{
 		config:     &valueConfig,
 		directives: definition.Directives,
 		node:       definition,
+		parentName: enumName,
+		parentKind: kinds.EnumDefinition,
 	}); err != nil {
 		return nil, err
 	}
@@ -96,6 +140,7 @@ func (c *registry) buildEnumValueFromAST(definition *ast.EnumValueDefinition, en
 func (c *registry) buildInputObjectFromAST(definition *ast.InputObjectDefinition) error {
 	var fields interface{}
 	name := definition.Name.Value
+	extensions := c.getInputObjectExtensions(name)
 	inputConfig := graphql.InputObjectConfig{
 		Name:        name,
 		Description: getDescription(definition),
@@ -105,24 +150,40 @@ func (c *registry) buildInputObjectFromAST(definition *ast.InputObjectDefinition
 	// use thunks only when allowed
 	if _, ok := c.dependencyMap[name]; ok {
 		var fields graphql.InputObjectConfigFieldMapThunk = func() graphql.InputObjectConfigFieldMap {
-			fieldMap, err := c.buildInputObjectFieldMapFromAST(definition.Fields)
+			fieldMap, err := c.buildInputObjectFieldMapFromAST(name, definition.Fields, extensions)
 			if err != nil {
+				c.addThunkError(err)
 				return nil
 			}
 			return fieldMap
 		}
 		inputConfig.Fields = fields
 	} else {
-		fieldMap, err := c.buildInputObjectFieldMapFromAST(definition.Fields)
+		fieldMap, err := c.buildInputObjectFieldMapFromAST(name, definition.Fields, extensions)
 		if err != nil {
 			return err
 		}
 		inputConfig.Fields = fieldMap
 	}
 
+	// update description from extensions if none
+	for _, extDef := range extensions {
+		if inputConfig.Description != "" {
+			break
+		}
+		inputConfig.Description = getDescription(extDef)
+	}
+
+	// create a combined directives array
+	directiveDefs := append([]*ast.Directive{}, definition.Directives...)
+	for _, extDef := range extensions {
+		directiveDefs = append(directiveDefs, extDef.Directives...)
+	}
+
 	if err := c.applyDirectives(applyDirectiveParams{
 		config:     &inputConfig,
-		directives: definition.Directives,
+		directives: directiveDefs,
+		extensions: extensions,
 		node:       definition,
 	}); err != nil {
 		return err
@@ -133,10 +194,20 @@ func (c *registry) buildInputObjectFromAST(definition *ast.InputObjectDefinition
 }
 
 // builds an input object field map from ast
-func (c *registry) buildInputObjectFieldMapFromAST(fields []*ast.InputValueDefinition) (graphql.InputObjectConfigFieldMap, error) {
+func (c *registry) buildInputObjectFieldMapFromAST(typeName string, fields []*ast.InputValueDefinition, extensions []*ast.InputObjectDefinition) (graphql.InputObjectConfigFieldMap, error) {
 	fieldMap := graphql.InputObjectConfigFieldMap{}
-	for _, fieldDef := range fields {
-		field, err := c.buildInputObjectFieldFromAST(fieldDef)
+
+	// build list of fields and append extensions
+	fieldDefs := append([]*ast.InputValueDefinition{}, fields...)
+	for _, extDef := range extensions {
+		fieldDefs = append(fieldDefs, extDef.Fields...)
+	}
+
+	for _, fieldDef := range fieldDefs {
+		if _, ok := fieldMap[fieldDef.Name.Value]; ok {
+			continue
+		}
+		field, err := c.buildInputObjectFieldFromAST(fieldDef, typeName)
 		if err != nil {
 			return nil, err
 		}
@@ -146,7 +217,7 @@ func (c *registry) buildInputObjectFieldMapFromAST(fields []*ast.InputValueDefin
 }
 
 // builds an input object field from an AST
-func (c *registry) buildInputObjectFieldFromAST(definition *ast.InputValueDefinition) (*graphql.InputObjectFieldConfig, error) {
+func (c *registry) buildInputObjectFieldFromAST(definition *ast.InputValueDefinition, typeName string) (*graphql.InputObjectFieldConfig, error) {
 	inputType, err := c.buildComplexType(definition.Type)
 	if err != nil {
 		return nil, err
@@ -167,6 +238,8 @@ func (c *registry) buildInputObjectFieldFromAST(definition *ast.InputValueDefini
 		config:     &field,
 		directives: definition.Directives,
 		node:       definition,
+		parentName: typeName,
+		parentKind: kinds.InputObjectDefinition,
 	}); err != nil {
 		return nil, err
 	}
@@ -188,6 +261,7 @@ func (c *registry) buildObjectFromAST(definition *ast.ObjectDefinition) error {
 		var ifaces graphql.InterfacesThunk = func() []*graphql.Interface {
 			ifaceArr, err := c.buildInterfacesArrayFromAST(definition, extensions)
 			if err != nil {
+				c.addThunkError(err)
 				return nil
 			}
 			return ifaceArr
@@ -197,6 +271,7 @@ func (c *registry) buildObjectFromAST(definition *ast.ObjectDefinition) error {
 		var fields graphql.FieldsThunk = func() graphql.Fields {
 			fieldMap, err := c.buildFieldMapFromAST(definition.Fields, definition.GetKind(), name, extensions)
 			if err != nil {
+				c.addThunkError(err)
 				return nil
 			}
 			return fieldMap
@@ -308,24 +383,31 @@ func (c *registry) buildFieldMapFromAST(fields []*ast.FieldDefinition, kind, typ
 
 // builds an interfacefrom ast
 func (c *registry) buildInterfaceFromAST(definition *ast.InterfaceDefinition) error {
-	extensions := []*ast.ObjectDefinition{}
 	name := definition.Name.Value
+	extensions := c.getInterfaceExtensions(name)
 	ifaceConfig := graphql.InterfaceConfig{
 		Name:        name,
 		Description: getDescription(definition),
 	}
 
+	// build list of fields and append extensions
+	fieldDefs := append([]*ast.FieldDefinition{}, definition.Fields...)
+	for _, extDef := range extensions {
+		fieldDefs = append(fieldDefs, extDef.Fields...)
+	}
+
 	if _, ok := c.dependencyMap[name]; ok {
 		var fields graphql.FieldsThunk = func() graphql.Fields {
-			fieldMap, err := c.buildFieldMapFromAST(definition.Fields, definition.GetKind(), name, extensions)
+			fieldMap, err := c.buildFieldMapFromAST(fieldDefs, definition.GetKind(), name, nil)
 			if err != nil {
+				c.addThunkError(err)
 				return nil
 			}
 			return fieldMap
 		}
 		ifaceConfig.Fields = fields
 	} else {
-		fieldMap, err := c.buildFieldMapFromAST(definition.Fields, definition.GetKind(), name, extensions)
+		fieldMap, err := c.buildFieldMapFromAST(fieldDefs, definition.GetKind(), name, nil)
 		if err != nil {
 			return err
 		}
@@ -336,9 +418,24 @@ func (c *registry) buildInterfaceFromAST(definition *ast.InterfaceDefinition) er
 		ifaceConfig.ResolveType = r.(*InterfaceResolver).ResolveType
 	}
 
+	// update description from extensions if none
+	for _, extDef := range extensions {
+		if ifaceConfig.Description != "" {
+			break
+		}
+		ifaceConfig.Description = getDescription(extDef)
+	}
+
+	// create a combined directives array
+	directiveDefs := append([]*ast.Directive{}, definition.Directives...)
+	for _, extDef := range extensions {
+		directiveDefs = append(directiveDefs, extDef.Directives...)
+	}
+
 	if err := c.applyDirectives(applyDirectiveParams{
 		config:     &ifaceConfig,
-		directives: definition.Directives,
+		directives: directiveDefs,
+		extensions: extensions,
 		node:       definition,
 	}); err != nil {
 		return err
@@ -348,8 +445,8 @@ func (c *registry) buildInterfaceFromAST(definition *ast.InterfaceDefinition) er
 	return nil
 }
 
-// builds an arg from an ast
-func (c *registry) buildArgFromAST(definition *ast.InputValueDefinition) (*graphql.ArgumentConfig, error) {
+// builds an arg from an ast, the field is nil for directive arguments
+func (c *registry) buildArgFromAST(definition *ast.InputValueDefinition, field *graphql.Field, kind, typeName string) (*graphql.ArgumentConfig, error) {
 	inputType, err := c.buildComplexType(definition.Type)
 	if err != nil {
 		return nil, err
@@ -370,6 +467,9 @@ func (c *registry) buildArgFromAST(definition *ast.InputValueDefinition) (*graph
 		config:     &arg,
 		directives: definition.Directives,
 		node:       definition,
+		field:      field,
+		parentName: typeName,
+		parentKind: kind,
 	}); err != nil {
 		return nil, err
 	}
@@ -385,17 +485,18 @@ func (c *registry) buildFieldFromAST(definition *ast.FieldDefinition, kind, type
 	}
 
 	field := graphql.Field{
-		Name:        definition.Name.Value,
-		Description: getDescription(definition),
-		Type:        fieldType,
-		Args:        graphql.FieldConfigArgument{},
-		Resolve:     c.getFieldResolveFn(kind, typeName, definition.Name.Value),
-		Subscribe:   c.getFieldSubscribeFn(kind, typeName, definition.Name.Value),
+		Name:              definition.Name.Value,
+		Description:       getDescription(definition),
+		Type:              fieldType,
+		Args:              graphql.FieldConfigArgument{},
+		Resolve:           c.getFieldResolveFn(kind, typeName, definition.Name.Value),
+		Subscribe:         c.getFieldSubscribeFn(kind, typeName, definition.Name.Value),
+		DeprecationReason: getDeprecationReason(definition.Directives),
 	}
 
 	for _, arg := range definition.Arguments {
 		if arg != nil {
-			argValue, err := c.buildArgFromAST(arg)
+			argValue, err := c.buildArgFromAST(arg, &field, kind, typeName)
 			if err != nil {
 				return nil, err
 			}
@@ -403,6 +504,8 @@ func (c *registry) buildFieldFromAST(definition *ast.FieldDefinition, kind, type
 		}
 	}
 
+	c.applyInputValidators(&field)
+
 	if err := c.applyDirectives(applyDirectiveParams{
 		config:     &field,
 		directives: definition.Directives,
@@ -413,20 +516,46 @@ func (c *registry) buildFieldFromAST(definition *ast.FieldDefinition, kind, type
 		return nil, err
 	}
 
+	// apply the directives inherited from the parent type and interfaces
+	if err := c.applyDirectives(applyDirectiveParams{
+		config:     &field,
+		directives: c.getInheritedFieldDirectives(definition, typeName),
+		node:       definition,
+		parentName: typeName,
+		parentKind: kind,
+		inherited:  true,
+	}); err != nil {
+		return nil, err
+	}
+
+	c.applyMiddleware(&field, definition, kind, typeName)
 	return &field, nil
 }
 
 // builds a union from ast
 func (c *registry) buildUnionFromAST(definition *ast.UnionDefinition) error {
 	name := definition.Name.Value
+	extensions := c.getUnionExtensions(name)
 	unionConfig := graphql.UnionConfig{
 		Name:        name,
 		Types:       []*graphql.Object{},
 		Description: getDescription(definition),
 	}
 
+	// build list of types and append extensions
+	typeDefs := append([]*ast.Named{}, definition.Types...)
+	for _, extDef := range extensions {
+		typeDefs = append(typeDefs, extDef.Types...)
+	}
+
 	// add types
-	for _, unionType := range definition.Types {
+	tmap := map[string]bool{}
+	for _, unionType := range typeDefs {
+		if tmap[unionType.Name.Value] {
+			continue
+		}
+		tmap[unionType.Name.Value] = true
+
 		object, err := c.getType(unionType.Name.Value)
 		if err != nil {
 			return err
@@ -448,9 +577,24 @@ func (c *registry) buildUnionFromAST(definition *ast.UnionDefinition) error {
 		}
 	}
 
+	// update description from extensions if none
+	for _, extDef := range extensions {
+		if unionConfig.Description != "" {
+			break
+		}
+		unionConfig.Description = getDescription(extDef)
+	}
+
+	// create a combined directives array
+	directiveDefs := append([]*ast.Directive{}, definition.Directives...)
+	for _, extDef := range extensions {
+		directiveDefs = append(directiveDefs, extDef.Directives...)
+	}
+
 	if err := c.applyDirectives(applyDirectiveParams{
 		config:     &unionConfig,
-		directives: definition.Directives,
+		directives: directiveDefs,
+		extensions: extensions,
 		node:       definition,
 	}); err != nil {
 		return err
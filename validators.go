@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/graphql-go/graphql"
+)
+
+// InputValidator validates the value of an argument or input field
+type InputValidator func(ctx context.Context, value interface{}) error
+
+// ValidationError an invalid argument or input field value. The path is the
+// location of the value starting at the field such as createUser.input.email
+type ValidationError struct {
+	Path    string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid value for %s: %s", e.Path, e.Message)
+}
+
+// Extensions returns the error extensions
+func (e *ValidationError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": "BAD_USER_INPUT",
+		"path": e.Path,
+	}
+}
+
+// creates a validation error for the path unless the error is already one
+func newValidationError(path string, err error) error {
+	if _, ok := err.(*ValidationError); ok {
+		return err
+	}
+	return &ValidationError{Path: path, Message: err.Error()}
+}
+
+// adds a validator for an input object field
+func (c *registry) addInputValidator(typeName, fieldName string, validator InputValidator) {
+	if validator == nil {
+		return
+	}
+	if _, ok := c.inputValidators[typeName]; !ok {
+		c.inputValidators[typeName] = map[string][]InputValidator{}
+	}
+	c.inputValidators[typeName][fieldName] = append(c.inputValidators[typeName][fieldName], validator)
+}
+
+// determines if input field validators can be added by the directive visitors
+func (c *registry) hasInputFieldVisitors() bool {
+	for _, visitor := range c.directiveMap {
+		if visitor != nil && visitor.VisitInputFieldDefinition != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// wraps the resolve function of fields with input object arguments to run the
+// input field validators before the resolver. Input object fields may be built
+// lazily so the validators are looked up when the field is resolved
+func (c *registry) applyInputValidators(field *graphql.Field) {
+	if !c.hasInputFieldVisitors() {
+		return
+	}
+
+	hasInputObject := false
+	for _, arg := range field.Args {
+		if _, ok := getNamedType(arg.Type).(*graphql.InputObject); ok {
+			hasInputObject = true
+			break
+		}
+	}
+	if !hasInputObject {
+		return
+	}
+
+	resolve := field.Resolve
+	if resolve == nil {
+		resolve = graphql.DefaultResolveFn
+	}
+
+	fieldName := field.Name
+	args := field.Args
+	field.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		if len(c.inputValidators) > 0 {
+			for _, name := range sortedArgNames(args) {
+				path := fieldName + "." + name
+				if err := c.validateInputValue(p.Context, args[name].Type, p.Args[name], path); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return resolve(p)
+	}
+}
+
+// validates the input object fields in a value
+func (c *registry) validateInputValue(ctx context.Context, t graphql.Type, value interface{}, path string) error {
+	if value == nil {
+		return nil
+	}
+
+	switch tt := t.(type) {
+	case *graphql.NonNull:
+		return c.validateInputValue(ctx, tt.OfType, value, path)
+
+	case *graphql.List:
+		if list, ok := value.([]interface{}); ok {
+			for i, item := range list {
+				if err := c.validateInputValue(ctx, tt.OfType, item, fmt.Sprintf("%s.%d", path, i)); err != nil {
+					return err
+				}
+			}
+		}
+
+	case *graphql.InputObject:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+
+		fields := tt.Fields()
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		validators := c.inputValidators[tt.Name()]
+		for _, name := range names {
+			fieldValue, ok := obj[name]
+			if !ok {
+				continue
+			}
+
+			fieldPath := path + "." + name
+			for _, validate := range validators[name] {
+				if err := validate(ctx, fieldValue); err != nil {
+					return newValidationError(fieldPath, err)
+				}
+			}
+			if err := c.validateInputValue(ctx, fields[name].Type, fieldValue, fieldPath); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// gets the argument names in a stable order
+func sortedArgNames(args graphql.FieldConfigArgument) []string {
+	names := make([]string, 0, len(args))
+	for name := range args {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}